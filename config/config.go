@@ -28,6 +28,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/user"
+	"path/filepath"
 	"strconv"
 )
 
@@ -37,6 +38,11 @@ const (
 	DefaultColors  = false
 	DefaultPS1     = "\\u@etcd:\\w\\$ "
 	DefaultPS2     = "> "
+	DefaultHome    = "/"
+
+	DefaultHistoryFile        = ".etcdsh_history"
+	DefaultHistorySize        = 500
+	DefaultHistoryIgnoreSpace = false
 )
 
 // Represents configuration file values.
@@ -45,6 +51,17 @@ type Config struct {
 	Colors  bool
 	PS1     string
 	PS2     string
+	Home    string
+
+	// HistoryFile is the file the command history is saved to. Relative paths are relative to
+	// the user's home directory.
+	HistoryFile string
+
+	// HistorySize is the number of commands kept in the history file.
+	HistorySize int
+
+	// HistoryIgnoreSpace prevents lines starting with a space from being added to the history.
+	HistoryIgnoreSpace bool
 }
 
 // Creates a new Config instance.
@@ -54,6 +71,11 @@ func New() *Config {
 		Colors:  getenvBool("COLORS", DefaultColors),
 		PS1:     getenvString("PS1", DefaultPS1),
 		PS2:     getenvString("PS2", DefaultPS2),
+		Home:    getenvString("HOME", DefaultHome),
+
+		HistoryFile:        getenvString("HISTORYFILE", DefaultHistoryFile),
+		HistorySize:        getenvInt("HISTORYSIZE", DefaultHistorySize),
+		HistoryIgnoreSpace: getenvBool("HISTORYIGNORESPACE", DefaultHistoryIgnoreSpace),
 	}
 
 	usr, err := user.Current()
@@ -72,6 +94,20 @@ func New() *Config {
 	return conf
 }
 
+// HistoryPath returns the absolute path to the history file, or an empty string when history
+// should not be saved.
+func (c *Config) HistoryPath() string {
+	if c.HistoryFile == "" || filepath.IsAbs(c.HistoryFile) {
+		return c.HistoryFile
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(usr.HomeDir, c.HistoryFile)
+}
+
 // getenv returns the value of an environment variable as a string or the default when the variable
 // is not set. The EnvPrefix constant is automatically prepended to the key.
 func getenvString(key, def string) string {
@@ -97,3 +133,17 @@ func getenvBool(key string, def bool) bool {
 
 	return def
 }
+
+// getenv returns the value of an environment variable as an int or the default when the variable
+// is not set or is not a number. The EnvPrefix constant is automatically prepended to the key.
+func getenvInt(key string, def int) int {
+	val := os.Getenv(EnvPrefix + key)
+	if val != "" {
+		n, err := strconv.Atoi(val)
+		if err == nil {
+			def = n
+		}
+	}
+
+	return def
+}
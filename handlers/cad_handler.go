@@ -0,0 +1,75 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+// CadHandler handles the "cad" command.
+type CadHandler struct {
+	controller *Controller
+}
+
+// NewCadHandler returns a new CadHandler instance.
+func NewCadHandler(controller *Controller) *CadHandler {
+	return &CadHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *CadHandler) Command() string {
+	return "cad"
+}
+
+// Validate returns whether the user input is valid.
+func (h *CadHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *CadHandler) Syntax() string {
+	return "cad [options] <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *CadHandler) Description() string {
+	return "Removes an object when it has the expected value or index"
+}
+
+// Handles the "cad" command.
+func (h *CadHandler) Handle(i *Input) (string, error) {
+	opts, args, err := setupCompareOptions(h, h.controller.Stdout(), i.Args, 1)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	client := h.controller.Client()
+	key := h.controller.WorkingDir(args[0])
+	_, err = client.CompareAndDelete(key, opts.PrevValue, opts.PrevIndex)
+	if err != nil {
+		return "", compareError(client, "cad", key, opts, err)
+	}
+	h.controller.RefreshWorkingDirKeys()
+
+	return "", nil
+}
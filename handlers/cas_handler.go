@@ -0,0 +1,156 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the cas and cad commands.
+type CompareOptions struct {
+	PrintHelp bool
+	PrevValue string
+	PrevIndex uint64
+	TTL       uint64
+}
+
+// CasHandler handles the "cas" command.
+type CasHandler struct {
+	controller *Controller
+}
+
+// NewCasHandler returns a new CasHandler instance.
+func NewCasHandler(controller *Controller) *CasHandler {
+	return &CasHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *CasHandler) Command() string {
+	return "cas"
+}
+
+// Validate returns whether the user input is valid.
+func (h *CasHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *CasHandler) Syntax() string {
+	return "cas [options] <path> <value>"
+}
+
+// Description returns a string that describes the command.
+func (h *CasHandler) Description() string {
+	return "Sets the value of an object when it has the expected value or index"
+}
+
+// Handles the "cas" command.
+func (h *CasHandler) Handle(i *Input) (string, error) {
+	opts, args, err := setupCompareOptions(h, h.controller.Stdout(), i.Args, 2)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	client := h.controller.Client()
+	key := h.controller.WorkingDir(args[0])
+	resp, err := client.CompareAndSwap(key, args[1], opts.TTL, opts.PrevValue, opts.PrevIndex)
+	if err != nil {
+		return "", compareError(client, "cas", key, opts, err)
+	}
+
+	return fmt.Sprintf("%s\n", resp.Node.Value), nil
+}
+
+// setupCompareOptions builds a FlagSet and parses the args passed to the cas and cad commands.
+// The number of args required after the options is given by nargs, and help is written to w.
+func setupCompareOptions(h Handler, w io.Writer, args []string, nargs int) (*CompareOptions, []string, error) {
+	opts := &CompareOptions{}
+	flags := flag.NewFlagSet(h.Command()+"_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.PrevValue, "prev-value", "", "Only when the current value equals this value")
+	flags.Uint64Var(&opts.PrevIndex, "prev-index", 0, "Only when the current modified index equals this index")
+	if h.Command() == "cas" {
+		flags.Uint64Var(&opts.TTL, "t", 0, "Sets the TTL")
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) != nargs {
+		printCommandHelp(w, h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+	if opts.PrevValue == "" && opts.PrevIndex == 0 {
+		return nil, nil, fmt.Errorf("%s: -prev-value or -prev-index is required", h.Command())
+	}
+
+	return opts, args, nil
+}
+
+// compareError returns an error describing which of the conditions failed when a compare
+// operation is rejected by the server, along with the current value and index of the object.
+// Other errors are returned unchanged.
+func compareError(client *etcd.Client, cmd, key string, opts *CompareOptions, err error) error {
+	if etcdErrorCode(err) != EcodeTestFailed {
+		return err
+	}
+
+	resp, getErr := client.Get(key, false, false)
+	if getErr != nil {
+		return fmt.Errorf("%s: compare failed for %s: %s", cmd, key, err)
+	}
+
+	node := resp.Node
+	failed := []string{}
+	if opts.PrevValue != "" && opts.PrevValue != node.Value {
+		failed = append(failed, fmt.Sprintf("prev-value %q does not match", opts.PrevValue))
+	}
+	if opts.PrevIndex != 0 && opts.PrevIndex != node.ModifiedIndex {
+		failed = append(failed, fmt.Sprintf("prev-index %d does not match", opts.PrevIndex))
+	}
+	if len(failed) == 0 {
+		failed = append(failed, "the object changed before it could be read")
+	}
+
+	return errors.New(fmt.Sprintf(
+		"%s: compare failed for %s: %s (current value %q, index %d)",
+		cmd,
+		key,
+		strings.Join(failed, ", "),
+		node.Value,
+		node.ModifiedIndex,
+	))
+}
@@ -24,6 +24,8 @@ THE SOFTWARE.
 
 package handlers
 
+import "errors"
+
 // CdHandler handles the "exit" command.
 type CdHandler struct {
 	controller *Controller
@@ -43,12 +45,12 @@ func (h *CdHandler) Command() string {
 
 // Validate returns whether the user input is valid.
 func (h *CdHandler) Validate(i *Input) bool {
-	return len(i.Args) > 0
+	return len(i.Args) < 2
 }
 
 // Syntax returns a string that demonstrates how to use the command.
 func (h *CdHandler) Syntax() string {
-	return "cd <directory>"
+	return "cd [directory|-]"
 }
 
 // Description returns a string that describes the command.
@@ -57,7 +59,27 @@ func (h *CdHandler) Description() string {
 }
 
 // Handles the "cd" command.
+//
+// Without a directory the working directory is changed to the configured home directory, and
+// "-" changes back to the previous working directory.
 func (h *CdHandler) Handle(i *Input) (string, error) {
-	h.controller.ChangeWorkingDir(i.Args[0])
-	return "", nil
+	if len(i.Args) == 0 {
+		_, err := h.controller.ChangeWorkingDir(h.controller.Config().Home)
+		return "", err
+	}
+	if i.Args[0] != "-" {
+		_, err := h.controller.ChangeWorkingDir(i.Args[0])
+		return "", err
+	}
+
+	old := h.controller.OldWorkingDir()
+	if old == "" {
+		return "", errors.New("cd: no previous directory")
+	}
+	wdir, err := h.controller.ChangeWorkingDir(old)
+	if err != nil {
+		return "", err
+	}
+
+	return wdir + "\n", nil
 }
@@ -0,0 +1,212 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How long to wait for a cluster member to respond.
+const ClusterRequestTimeout = 5 * time.Second
+
+// A member of the cluster returned by /v2/members.
+type clusterMember struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	PeerURLs   []string `json:"peerURLs"`
+	ClientURLs []string `json:"clientURLs"`
+}
+
+// The response of /v2/members.
+type clusterMembers struct {
+	Members []clusterMember `json:"members"`
+}
+
+// The response of /v2/stats/self.
+type selfStats struct {
+	Name       string `json:"name"`
+	ID         string `json:"id"`
+	State      string `json:"state"`
+	StartTime  string `json:"startTime"`
+	LeaderInfo struct {
+		Leader    string `json:"leader"`
+		Uptime    string `json:"uptime"`
+		StartTime string `json:"startTime"`
+	} `json:"leaderInfo"`
+	RecvAppendRequestCnt uint64  `json:"recvAppendRequestCnt"`
+	RecvPkgRate          float64 `json:"recvPkgRate"`
+	RecvBandwidthRate    float64 `json:"recvBandwidthRate"`
+	SendAppendRequestCnt uint64  `json:"sendAppendRequestCnt"`
+	SendPkgRate          float64 `json:"sendPkgRate"`
+	SendBandwidthRate    float64 `json:"sendBandwidthRate"`
+}
+
+// The response of /v2/stats/leader.
+type leaderStats struct {
+	Leader    string `json:"leader"`
+	Followers map[string]struct {
+		Latency struct {
+			Current           float64 `json:"current"`
+			Average           float64 `json:"average"`
+			StandardDeviation float64 `json:"standardDeviation"`
+			Minimum           float64 `json:"minimum"`
+			Maximum           float64 `json:"maximum"`
+		} `json:"latency"`
+		Counts struct {
+			Fail    uint64 `json:"fail"`
+			Success uint64 `json:"success"`
+		} `json:"counts"`
+	} `json:"followers"`
+}
+
+// clusterMachines returns the client URLs of the machines known to the controller. The
+// cluster is synced first so every member is tried, and the configured machine is used when
+// the cluster cannot be synced.
+func (c *Controller) clusterMachines() []string {
+	c.client.SyncCluster()
+	machines := c.client.GetCluster()
+	if len(machines) == 0 {
+		machines = []string{c.config.Machine}
+	}
+
+	return machines
+}
+
+// clusterGet requests the given path from each machine in turn until one responds, and
+// returns the body of the response. The body is decoded into v when v is not nil.
+func (c *Controller) clusterGet(path string, v interface{}) ([]byte, error) {
+	return machinesGet(c.clusterMachines(), path, v)
+}
+
+// clusterLeader returns the member which is the leader of the cluster, along with the
+// statistics of the machine which reported it.
+func (c *Controller) clusterLeader() (clusterMember, *selfStats, error) {
+	stats := &selfStats{}
+	_, err := c.clusterGet("/v2/stats/self", stats)
+	if err != nil {
+		return clusterMember{}, nil, err
+	}
+	members := &clusterMembers{}
+	_, err = c.clusterGet("/v2/members", members)
+	if err != nil {
+		return clusterMember{}, nil, err
+	}
+
+	for _, m := range members.Members {
+		if m.ID == stats.LeaderInfo.Leader {
+			return m, stats, nil
+		}
+	}
+
+	return clusterMember{}, nil, errors.New("the cluster has no leader")
+}
+
+// leaderGet requests the given path from the leader of the cluster, for requests such as
+// /v2/stats/leader which followers refuse. The body is decoded into v when v is not nil.
+func (c *Controller) leaderGet(path string, v interface{}) ([]byte, error) {
+	leader, _, err := c.clusterLeader()
+	if err != nil {
+		return nil, err
+	}
+	if len(leader.ClientURLs) == 0 {
+		return nil, fmt.Errorf("the leader %s has no client URLs", leader.ID)
+	}
+
+	return machinesGet(leader.ClientURLs, path, v)
+}
+
+// machinesGet requests the given path from each of the machines in turn until one responds,
+// and returns the body of the response. The body is decoded into v when v is not nil.
+func machinesGet(machines []string, path string, v interface{}) ([]byte, error) {
+	var lastErr error
+	for _, machine := range machines {
+		body, err := machineGet(machine, path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if v != nil {
+			err = json.Unmarshal(body, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid response from %s%s: %s", machine, path, err)
+			}
+		}
+		return body, nil
+	}
+
+	return nil, lastErr
+}
+
+// machineGet requests the given path from a single machine and returns the body.
+func machineGet(machine, path string) ([]byte, error) {
+	client := &http.Client{Timeout: ClusterRequestTimeout}
+	url := strings.TrimSuffix(machine, "/") + path
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	return body, nil
+}
+
+// indentJSON returns the JSON body indented for display.
+func indentJSON(body []byte) (string, error) {
+	buffer := bytes.NewBufferString("")
+	err := json.Indent(buffer, body, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	buffer.WriteString("\n")
+
+	return buffer.String(), nil
+}
+
+// memberName returns the name of the member with the given id, or the id when the member is
+// unknown or unnamed.
+func memberName(members *clusterMembers, id string) string {
+	for _, m := range members.Members {
+		if m.ID == id && m.Name != "" {
+			return m.Name
+		}
+	}
+
+	return id
+}
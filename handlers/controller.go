@@ -26,6 +26,7 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -35,7 +36,6 @@ import (
 
 	"github.com/bobappleyard/readline"
 	"github.com/coreos/go-etcd/etcd"
-	"github.com/flynn/go-shlex"
 	"github.com/headzoo/etcdsh/config"
 	"github.com/headzoo/etcdsh/etcdsh"
 	"github.com/headzoo/etcdsh/parser"
@@ -44,25 +44,31 @@ import (
 // Controller stores handlers and calls them.
 type Controller struct {
 	wdir                  string
+	oldwdir               string
+	wdirStack             []string
 	wdirKeys              []string
+	history               *History
 	handlers              HandlerMap
 	config                *config.Config
 	client                *etcd.Client
 	stdout, stderr, stdin *os.File
 	prompter              *parser.Prompt
+	nonInteractive        bool
 }
 
 // Create a new Controller.
 func NewController(conf *config.Config, client *etcd.Client, stdout, stderr, stdin *os.File) *Controller {
 	c := &Controller{
-		config:   conf,
-		client:   client,
-		stdout:   stdout,
-		stdin:    stdin,
-		stderr:   stderr,
-		wdir:     "/",
-		handlers: make(HandlerMap),
-		prompter: parser.NewPrompt(),
+		config:    conf,
+		client:    client,
+		stdout:    stdout,
+		stdin:     stdin,
+		stderr:    stderr,
+		wdir:      "/",
+		wdirStack: []string{},
+		handlers:  make(HandlerMap),
+		history:   NewHistory(),
+		prompter:  parser.NewPrompt(),
 	}
 
 	c.prompter.AddFormatter('w', func() string {
@@ -89,11 +95,18 @@ func NewController(conf *config.Config, client *etcd.Client, stdout, stderr, std
 // Starts the controller.
 func (c *Controller) Start() int {
 	c.welcome()
-	c.ChangeWorkingDir("/")
+	_, err := c.ChangeWorkingDir("/")
+	if err != nil {
+		panic(err)
+	}
 
 	readline.Completer = c.filenameCompleter
+	c.loadHistory()
+	defer c.saveHistory()
+
 	buffer := bytes.NewBufferString("")
 	prompt := ""
+	ignore := false
 
 	for {
 		if buffer.Len() == 0 {
@@ -110,10 +123,10 @@ func (c *Controller) Start() int {
 			panic(err)
 		}
 
-		line = strings.TrimSpace(line)
-		if strings.ToLower(line) == "q" || strings.ToLower(line) == "exit" {
-			return 0
+		if buffer.Len() == 0 {
+			ignore = c.config.HistoryIgnoreSpace && strings.HasPrefix(line, " ")
 		}
+		line = strings.TrimSpace(line)
 		if strings.HasSuffix(line, "\\") {
 			buffer.WriteString(strings.TrimSuffix(line, "\\") + "\n")
 		} else {
@@ -123,17 +136,26 @@ func (c *Controller) Start() int {
 				buffer.Reset()
 			}
 
-			parts, err := shlex.Split(line)
+			var expanded bool
+			line, expanded, err = c.history.Expand(line)
 			if err != nil {
-				panic(err)
+				fmt.Fprintln(c.stderr, err)
+				continue
+			}
+			if expanded {
+				fmt.Fprintln(c.stdout, line)
+			}
+			if isExitCommand(line) {
+				return 0
 			}
-			readline.AddHistory(line)
 
-			in := NewInput(parts[0])
-			if len(parts) > 1 {
-				in.Args = parts[1:]
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if !ignore {
+				c.history.Add(line)
 			}
-			c.handleInput(in)
+			c.runLine(line, false)
 		}
 	}
 
@@ -150,6 +172,63 @@ func (c *Controller) Config() *config.Config {
 	return c.config
 }
 
+// History returns the command history.
+func (c *Controller) History() *History {
+	return c.history
+}
+
+// Stdout returns the writer used for command output.
+func (c *Controller) Stdout() io.Writer {
+	return c.stdout
+}
+
+// Stderr returns the writer used for error output.
+func (c *Controller) Stderr() io.Writer {
+	return c.stderr
+}
+
+// Stdin returns the reader used for user input.
+func (c *Controller) Stdin() io.Reader {
+	return c.stdin
+}
+
+// loadHistory loads the history saved by previous sessions.
+func (c *Controller) loadHistory() {
+	path := c.config.HistoryPath()
+	if path == "" {
+		return
+	}
+	err := c.history.Load(path)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "Unable to load history from %s: %s\n", path, err)
+	}
+}
+
+// saveHistory saves the history for the next session.
+func (c *Controller) saveHistory() {
+	path := c.config.HistoryPath()
+	if path == "" {
+		return
+	}
+	err := c.history.Save(path, c.config.HistorySize)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "Unable to save history to %s: %s\n", path, err)
+	}
+}
+
+// ErrNotInteractive is returned by Prompt when commands are not being typed by a user.
+var ErrNotInteractive = errors.New("requires an interactive shell")
+
+// Prompt displays a prompt to the user and returns the line they enter. ErrNotInteractive is
+// returned when running a script, where the next line would be a command rather than an answer.
+func (c *Controller) Prompt(prompt string) (string, error) {
+	if c.nonInteractive {
+		return "", ErrNotInteractive
+	}
+
+	return readline.String(prompt)
+}
+
 // Add appends a handler to the map.
 func (c *Controller) Add(h Handler) {
 	c.handlers[h.Command()] = h
@@ -160,31 +239,132 @@ func (c *Controller) Handlers() HandlerMap {
 	return c.handlers
 }
 
-// WorkingDir returns the working directory. The value of a is appended to the value.
+// WorkingDir returns the working directory. The value of a is appended to the value, unless
+// a is an absolute path, in which case the cleaned value of a is returned.
 func (c *Controller) WorkingDir(a string) string {
 	a = strings.Replace(a, "\n", "", -1)
+	if strings.HasPrefix(a, "/") {
+		return path.Clean(a)
+	}
 	wdir := c.wdir + "/" + a
 	return path.Clean(wdir)
 }
 
-// ChangeWorkingDir changes the current working directory.
-func (c *Controller) ChangeWorkingDir(wdir string) string {
-	if strings.HasPrefix(wdir, "/") {
-		c.wdir = wdir
-	} else {
-		c.wdir = c.WorkingDir("/" + wdir)
+// ChangeWorkingDir changes the current working directory. An error is returned, and the
+// working directory is left unchanged, when wdir is not an existing key.
+func (c *Controller) ChangeWorkingDir(wdir string) (string, error) {
+	wdir = c.WorkingDir(wdir)
+	keys, err := c.workingDirKeys(wdir)
+	if err != nil {
+		return c.wdir, err
+	}
+
+	if wdir != c.wdir {
+		c.oldwdir = c.wdir
+	}
+	c.wdir = wdir
+	c.wdirKeys = keys
+
+	return c.wdir, nil
+}
+
+// OldWorkingDir returns the previous working directory, or an empty string when the working
+// directory has not been changed.
+func (c *Controller) OldWorkingDir() string {
+	return c.oldwdir
+}
+
+// WorkingDirStack returns the directory stack. The first entry is always the working
+// directory.
+func (c *Controller) WorkingDirStack() []string {
+	return append([]string{c.wdir}, c.wdirStack...)
+}
+
+// PushWorkingDir changes the working directory to wdir, and pushes the previous working
+// directory onto the directory stack.
+func (c *Controller) PushWorkingDir(wdir string) (string, error) {
+	old := c.wdir
+	wdir, err := c.ChangeWorkingDir(wdir)
+	if err != nil {
+		return wdir, err
+	}
+	c.wdirStack = append([]string{old}, c.wdirStack...)
+
+	return wdir, nil
+}
+
+// RotateWorkingDirStack rotates the directory stack so the entry at index n becomes the
+// working directory.
+func (c *Controller) RotateWorkingDirStack(n int) (string, error) {
+	stack := c.WorkingDirStack()
+	if n < 0 || n >= len(stack) {
+		return c.wdir, fmt.Errorf("directory stack index out of range: %d", n)
 	}
 
-	resp, err := c.client.Get(c.wdir, true, true)
+	rotated := append(stack[n:], stack[:n]...)
+	wdir, err := c.ChangeWorkingDir(rotated[0])
 	if err != nil {
-		panic(err)
+		return wdir, err
+	}
+	c.wdirStack = rotated[1:]
+
+	return wdir, nil
+}
+
+// PopWorkingDir removes the entry at index n from the directory stack. Removing the first
+// entry changes the working directory to the next entry.
+func (c *Controller) PopWorkingDir(n int) (string, error) {
+	if len(c.wdirStack) == 0 {
+		return c.wdir, errors.New("directory stack empty")
+	}
+	if n < 0 || n > len(c.wdirStack) {
+		return c.wdir, fmt.Errorf("directory stack index out of range: %d", n)
+	}
+
+	if n == 0 {
+		wdir, err := c.ChangeWorkingDir(c.wdirStack[0])
+		if err != nil {
+			return wdir, err
+		}
+		c.wdirStack = c.wdirStack[1:]
+		return wdir, nil
+	}
+	c.wdirStack = append(c.wdirStack[:n-1], c.wdirStack[n:]...)
+
+	return c.wdir, nil
+}
+
+// ClearWorkingDirStack removes every entry from the directory stack except the working
+// directory.
+func (c *Controller) ClearWorkingDirStack() {
+	c.wdirStack = []string{}
+}
+
+// RefreshWorkingDirKeys reloads the keys used for tab completion. Handlers which add or remove
+// keys should call this method so the completer does not offer stale keys.
+func (c *Controller) RefreshWorkingDirKeys() error {
+	keys, err := c.workingDirKeys(c.wdir)
+	c.wdirKeys = keys
+
+	return err
+}
+
+// workingDirKeys returns every key below wdir. An error is returned when wdir is not an
+// existing key.
+func (c *Controller) workingDirKeys(wdir string) ([]string, error) {
+	resp, err := c.client.Get(wdir, true, true)
+	if err != nil {
+		return []string{}, err
+	}
+	if !resp.Node.Dir {
+		return []string{}, fmt.Errorf("%s is an object, not a key", wdir)
 	}
 
 	count := c.getNodeCount(resp.Node, 0)
 	c.wdirKeys = make([]string, count)
 	c.addNodeToWorkingDirKeys(resp.Node, 0)
 
-	return c.wdir
+	return c.wdirKeys, nil
 }
 
 // addNodeToWDir adds the keys from all child nodes to the working dir keys.
@@ -226,21 +406,33 @@ func (c *Controller) hasHandler(id string) bool {
 	return ok
 }
 
-// Handles the user input.
-func (c *Controller) handleInput(i *Input) {
+// Handles the user input. Returns the exit status of the command, which is 0 when the command
+// succeeded.
+func (c *Controller) handleInput(i *Input) int {
 	handler, ok := c.handlers[i.Cmd]
 	if !ok {
 		fmt.Fprintln(c.stderr, fmt.Sprintf("The command %s does not exist.", i.Cmd))
+		return StatusFailure
 	} else if !handler.Validate(i) {
 		fmt.Fprintln(c.stderr, fmt.Sprintf("Invalid use of command, use: %s", handler.Syntax()))
-	} else {
-		output, err := handler.Handle(i)
-		if err == nil {
-			fmt.Fprint(c.stdout, output)
-		} else {
-			fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+
+	output, err := handler.Handle(i)
+	fmt.Fprint(c.stdout, output)
+	if err == nil {
+		return StatusSuccess
+	}
+
+	if e, ok := err.(*StatusError); ok {
+		if e.Message != "" {
+			fmt.Fprintln(c.stderr, e.Message)
 		}
+		return e.Status
 	}
+	fmt.Fprintln(c.stderr, err)
+
+	return StatusFailure
 }
 
 // filenameCompleter is a callback function for the readline.Completer variable.
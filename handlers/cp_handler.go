@@ -0,0 +1,175 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the cp command.
+type CpOptions struct {
+	PrintHelp bool
+	Recursive bool
+}
+
+// CpHandler handles the "cp" command.
+type CpHandler struct {
+	controller *Controller
+}
+
+// NewCpHandler returns a new CpHandler instance.
+func NewCpHandler(controller *Controller) *CpHandler {
+	return &CpHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *CpHandler) Command() string {
+	return "cp"
+}
+
+// Validate returns whether the user input is valid.
+func (h *CpHandler) Validate(i *Input) bool {
+	return len(i.Args) > 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *CpHandler) Syntax() string {
+	return "cp [options] <source> <dest>"
+}
+
+// Description returns a string that describes the command.
+func (h *CpHandler) Description() string {
+	return "Copies an object, or a key and its contents when used with -r"
+}
+
+// Handles the "cp" command.
+func (h *CpHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	client := h.controller.Client()
+	src := h.controller.WorkingDir(args[0])
+	resp, err := client.Get(src, true, true)
+	if err != nil {
+		return "", err
+	}
+	if resp.Node.Dir && !opts.Recursive {
+		return "", fmt.Errorf("cp: omitting key '%s', use -r to copy keys", args[0])
+	}
+
+	dst, err := copyDestination(client, resp.Node, h.controller.WorkingDir(args[1]))
+	if err != nil {
+		return "", err
+	}
+	err = copyNode(client, resp.Node, dst)
+	h.controller.RefreshWorkingDirKeys()
+
+	return "", err
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *CpHandler) setupOptions(args []string) (*CpOptions, []string, error) {
+	opts := &CpOptions{}
+	flags := flag.NewFlagSet("cp_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Recursive, "r", false, "Copy keys recursively")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) < 2 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
+
+// copyDestination returns the key where node will be copied when the user gives dst as the
+// destination. Like the cp shell command, copying into an existing key places the copy below
+// that key using the base name of the source.
+func copyDestination(client *etcd.Client, node *etcd.Node, dst string) (string, error) {
+	resp, err := client.Get(dst, false, false)
+	if err != nil {
+		if etcdErrorCode(err) == EcodeKeyNotFound {
+			err = nil
+		}
+	} else if resp.Node.Dir {
+		dst = path.Join(dst, path.Base(node.Key))
+	} else if node.Dir {
+		err = fmt.Errorf("cannot overwrite object '%s' with key '%s'", dst, node.Key)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if dst == node.Key {
+		return "", fmt.Errorf("'%s' and '%s' are the same", node.Key, dst)
+	}
+	if strings.HasPrefix(dst, node.Key+"/") {
+		return "", fmt.Errorf("cannot copy '%s' into itself", node.Key)
+	}
+
+	return dst, nil
+}
+
+// copyNode recreates node and every node below it at dst. The remaining TTL of each node is
+// carried over to the copy.
+func copyNode(client *etcd.Client, node *etcd.Node, dst string) error {
+	ttl := uint64(0)
+	if node.TTL > 0 {
+		ttl = uint64(node.TTL)
+	}
+
+	if !node.Dir {
+		_, err := client.Set(dst, node.Value, ttl)
+		return err
+	}
+
+	err := ensureDir(client, dst, ttl)
+	if err != nil {
+		return err
+	}
+	for _, n := range node.Nodes {
+		err = copyNode(client, n, path.Join(dst, path.Base(n.Key)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/headzoo/etcdsh/env"
+)
+
+const (
+	// Color used for removed lines in the "diff" output.
+	DefaultColorRemoved = "31"
+
+	// Color used for added lines in the "diff" output.
+	DefaultColorAdded = "32"
+)
+
+// Command line options for the diff command.
+type DiffOptions struct {
+	PrintHelp bool
+	Format    string
+}
+
+// DiffHandler handles the "diff" command.
+type DiffHandler struct {
+	controller *Controller
+	use_colors bool
+}
+
+// NewDiffHandler returns a new DiffHandler instance.
+func NewDiffHandler(controller *Controller) *DiffHandler {
+	h := &DiffHandler{
+		controller: controller,
+	}
+	_, h.use_colors = outputColors(controller.Config())
+
+	return h
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *DiffHandler) Command() string {
+	return "diff"
+}
+
+// Validate returns whether the user input is valid.
+func (h *DiffHandler) Validate(i *Input) bool {
+	return len(i.Args) > 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *DiffHandler) Syntax() string {
+	return "diff [options] <path> <path|file>"
+}
+
+// Description returns a string that describes the command.
+func (h *DiffHandler) Description() string {
+	return "Compares the objects below two keys, or below a key and in a file"
+}
+
+// Handles the "diff" command.
+//
+// Objects are compared by their path relative to each root. When the second argument names an
+// existing local file, the file is compared instead of a second key. Like the diff shell
+// command, the status is 1 when there are differences and 2 when the comparison failed.
+func (h *DiffHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", diffTrouble(err)
+	}
+
+	var objectsB map[string]string
+	nameB := args[1]
+	info, statErr := os.Stat(args[1])
+	if statErr == nil && !info.IsDir() {
+		objectsB, err = h.fileObjects(args[1], opts.Format)
+	} else {
+		nameB = h.controller.WorkingDir(args[1])
+		objectsB, err = h.keyObjects(nameB)
+	}
+	if err != nil {
+		return "", diffTrouble(err)
+	}
+
+	rootA := h.controller.WorkingDir(args[0])
+	objectsA, err := h.keyObjects(rootA)
+	if err != nil {
+		return "", diffTrouble(err)
+	}
+
+	lines := diffObjects(objectsA, objectsB)
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	output := bytes.NewBufferString("")
+	output.WriteString(h.colorize(fmt.Sprintf("--- %s\n", rootA)))
+	output.WriteString(h.colorize(fmt.Sprintf("+++ %s\n", nameB)))
+	for _, line := range lines {
+		output.WriteString(h.colorize(line + "\n"))
+	}
+
+	return output.String(), &StatusError{Status: StatusFailure}
+}
+
+// diffTrouble returns err with the status used when the comparison could not be made, so
+// scripts can tell a failure from differences. A nil err, or an err which already has a
+// status, is returned unchanged.
+func diffTrouble(err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*StatusError); ok {
+		return &StatusError{Status: StatusDiffTrouble, Message: e.Message}
+	}
+
+	return &StatusError{Status: StatusDiffTrouble, Message: err.Error()}
+}
+
+// keyObjects returns the objects below the root key, using paths relative to the root.
+func (h *DiffHandler) keyObjects(root string) (map[string]string, error) {
+	resp, err := h.controller.Client().Get(root, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Node.Dir {
+		return nil, fmt.Errorf("diff: %s is an object, not a key", root)
+	}
+
+	objects := make(map[string]string)
+	flattenNode(objects, resp.Node, root)
+	return objects, nil
+}
+
+// fileObjects returns the objects in a local file.
+func (h *DiffHandler) fileObjects(filename, format string) (map[string]string, error) {
+	if format == "" {
+		format = formatFromFilename(filename)
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	doc, _, err := decodeDocument(format, b)
+	if err != nil {
+		return nil, fmt.Errorf("diff: cannot read %s: %s", filename, err)
+	}
+	objects, _, err := flattenDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("diff: cannot read %s: %s", filename, err)
+	}
+
+	return objects, nil
+}
+
+// colorize colors a line of output by its first character when colors are enabled.
+func (h *DiffHandler) colorize(line string) string {
+	if !h.use_colors || len(line) == 0 {
+		return line
+	}
+
+	color := ""
+	switch line[0] {
+	case '-':
+		color = DefaultColorRemoved
+	case '+':
+		color = DefaultColorAdded
+	default:
+		return line
+	}
+
+	return env.ColorPrefixCode(color) + line[:len(line)-1] + env.ColorPostfixCode() + "\n"
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *DiffHandler) setupOptions(args []string) (*DiffOptions, []string, error) {
+	opts := &DiffOptions{}
+	flags := flag.NewFlagSet("diff_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Format, "format", "", "File format: json, yaml, env or flat (default from the file extension)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) != 2 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+	if opts.Format != "" && !isDocumentFormat(opts.Format) {
+		return nil, nil, fmt.Errorf("diff: unknown format '%s'", opts.Format)
+	}
+
+	return opts, args, nil
+}
+
+// diffObjects compares two sets of objects, and returns a line for each object which was
+// removed from a (prefixed with "-"), added in b (prefixed with "+"), or changed (both).
+func diffObjects(a, b map[string]string) []string {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := []string{}
+	for _, key := range keys {
+		valueA, inA := a[key]
+		valueB, inB := b[key]
+		if inA && inB && valueA == valueB {
+			continue
+		}
+		if inA {
+			lines = append(lines, fmt.Sprintf("-%s = %s", key, strconv.Quote(valueA)))
+		}
+		if inB {
+			lines = append(lines, fmt.Sprintf("+%s = %s", key, strconv.Quote(valueB)))
+		}
+	}
+
+	return lines
+}
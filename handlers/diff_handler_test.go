@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDiffObjects(t *testing.T) {
+	a := map[string]string{
+		"db/host": "db1",
+		"port":    "80",
+		"web":     "http://example.com",
+	}
+	b := map[string]string{
+		"db/host": "db2",
+		"port":    "80",
+		"tls":     "true",
+	}
+	expected := []string{
+		`-db/host = "db1"`,
+		`+db/host = "db2"`,
+		`+tls = "true"`,
+		`-web = "http://example.com"`,
+	}
+	actual := diffObjects(a, b)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("diffObjects() = %v, want %v.", actual, expected)
+	}
+
+	if len(diffObjects(a, a)) != 0 {
+		t.Errorf("diffObjects() = %v, want [].", diffObjects(a, a))
+	}
+}
+
+func TestDiffHandlerTrouble(t *testing.T) {
+	file, err := ioutil.TempFile("", "etcdsh-diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("{not json")
+	file.Close()
+
+	c, _ := testController(t)
+	in := NewInput("diff")
+	in.Args = []string{"-format", FormatJSON, "/apps", file.Name()}
+	_, err = NewDiffHandler(c).Handle(in)
+	e, ok := err.(*StatusError)
+	if !ok || e.Status != StatusDiffTrouble || e.Message == "" {
+		t.Errorf("Handle() error = %v, want status %d with a message.", err, StatusDiffTrouble)
+	}
+}
@@ -0,0 +1,131 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+)
+
+// Command line options for the dirs command.
+type DirsOptions struct {
+	PrintHelp bool
+	Clear     bool
+	Verbose   bool
+}
+
+// DirsHandler handles the "dirs" command.
+type DirsHandler struct {
+	controller *Controller
+}
+
+// NewDirsHandler returns a new DirsHandler instance.
+func NewDirsHandler(controller *Controller) *DirsHandler {
+	return &DirsHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *DirsHandler) Command() string {
+	return "dirs"
+}
+
+// Validate returns whether the user input is valid.
+func (h *DirsHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *DirsHandler) Syntax() string {
+	return "dirs [options] [+N|-N]"
+}
+
+// Description returns a string that describes the command.
+func (h *DirsHandler) Description() string {
+	return "Displays the directory stack"
+}
+
+// Handles the "dirs" command.
+func (h *DirsHandler) Handle(i *Input) (string, error) {
+	// Stack indexes look like flags, so they are removed before the flags are parsed.
+	args := []string{}
+	index := ""
+	for _, arg := range i.Args {
+		if isStackIndex(arg) {
+			index = arg
+		} else {
+			args = append(args, arg)
+		}
+	}
+
+	opts, err := h.setupOptions(args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+	if opts.Clear {
+		h.controller.ClearWorkingDirStack()
+		return "", nil
+	}
+
+	stack := h.controller.WorkingDirStack()
+	if index != "" {
+		n, err := stackIndex(index, len(stack))
+		if err != nil {
+			return "", fmt.Errorf("dirs: %s", err)
+		}
+		return stack[n] + "\n", nil
+	}
+	if !opts.Verbose {
+		return formatWorkingDirStack(stack), nil
+	}
+
+	output := bytes.NewBufferString("")
+	for n, dir := range stack {
+		output.WriteString(fmt.Sprintf("%2d  %s\n", n, dir))
+	}
+	return output.String(), nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *DirsHandler) setupOptions(args []string) (*DirsOptions, error) {
+	opts := &DirsOptions{}
+	flags := flag.NewFlagSet("dirs_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Clear, "c", false, "Clear the directory stack")
+	flags.BoolVar(&opts.Verbose, "v", false, "Display one directory per line with its index")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,375 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/headzoo/etcdsh/etcdsh"
+	"gopkg.in/yaml.v2"
+)
+
+// Document formats supported by the export and import commands.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatEnv  = "env"
+	FormatFlat = "flat"
+)
+
+// Wraps an exported document along with the metadata of each node.
+type documentEnvelope struct {
+	Etcdsh    string              `json:"etcdsh" yaml:"etcdsh"`
+	Path      string              `json:"path" yaml:"path"`
+	EtcdIndex uint64              `json:"etcdIndex" yaml:"etcdIndex"`
+	Data      interface{}         `json:"data" yaml:"data"`
+	Meta      map[string]nodeMeta `json:"meta" yaml:"meta"`
+}
+
+// The metadata of a single node in a document envelope.
+type nodeMeta struct {
+	Dir           bool   `json:"dir,omitempty" yaml:"dir,omitempty"`
+	TTL           int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	CreatedIndex  uint64 `json:"createdIndex" yaml:"createdIndex"`
+	ModifiedIndex uint64 `json:"modifiedIndex" yaml:"modifiedIndex"`
+}
+
+// isDocumentFormat returns whether format is one of the supported document formats.
+func isDocumentFormat(format string) bool {
+	switch format {
+	case FormatJSON, FormatYAML, FormatEnv, FormatFlat:
+		return true
+	}
+
+	return false
+}
+
+// relativeKey returns the key relative to the root key, without a leading slash.
+func relativeKey(root, key string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(key, root), "/")
+}
+
+// nodeToDocument converts a node into a document, where keys are maps and objects are
+// strings.
+func nodeToDocument(node *etcd.Node) interface{} {
+	if !node.Dir {
+		return node.Value
+	}
+
+	doc := make(map[string]interface{})
+	for _, n := range node.Nodes {
+		doc[path.Base(n.Key)] = nodeToDocument(n)
+	}
+
+	return doc
+}
+
+// newDocumentEnvelope wraps the document created from the response along with the metadata
+// of every node below root.
+func newDocumentEnvelope(resp *etcd.Response, root string) *documentEnvelope {
+	envelope := &documentEnvelope{
+		Etcdsh:    etcdsh.Version,
+		Path:      root,
+		EtcdIndex: resp.EtcdIndex,
+		Data:      nodeToDocument(resp.Node),
+		Meta:      make(map[string]nodeMeta),
+	}
+	addNodeMeta(envelope.Meta, resp.Node, root)
+
+	return envelope
+}
+
+// addNodeMeta adds the metadata of node, and every node below it, to meta.
+func addNodeMeta(meta map[string]nodeMeta, node *etcd.Node, root string) {
+	key := relativeKey(root, node.Key)
+	if key != "" {
+		meta[key] = nodeMeta{
+			Dir:           node.Dir,
+			TTL:           node.TTL,
+			CreatedIndex:  node.CreatedIndex,
+			ModifiedIndex: node.ModifiedIndex,
+		}
+	}
+	for _, n := range node.Nodes {
+		addNodeMeta(meta, n, root)
+	}
+}
+
+// flattenNode adds the value of every object below node to objects, using keys relative to
+// root.
+func flattenNode(objects map[string]string, node *etcd.Node, root string) {
+	if !node.Dir {
+		objects[relativeKey(root, node.Key)] = node.Value
+		return
+	}
+	for _, n := range node.Nodes {
+		flattenNode(objects, n, root)
+	}
+}
+
+// flattenDocument converts a document into a map of object paths to values. The paths of
+// empty maps are returned separately, so they can be created as empty keys. Numbers and
+// booleans are converted to strings, and the items of a list are named by their position.
+func flattenDocument(doc interface{}) (map[string]string, []string, error) {
+	switch doc.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+	default:
+		return nil, nil, fmt.Errorf("the document must contain a map, not a single value")
+	}
+
+	objects := make(map[string]string)
+	dirs := []string{}
+	err := flattenValue(objects, &dirs, "", doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(dirs)
+
+	return objects, dirs, nil
+}
+
+// flattenValue adds value to objects or dirs using the given key, descending into maps and
+// lists.
+func flattenValue(objects map[string]string, dirs *[]string, key string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && key != "" {
+			*dirs = append(*dirs, key)
+		}
+		for name, child := range v {
+			err := flattenValue(objects, dirs, path.Join(key, name), child)
+			if err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for name, child := range v {
+			m[fmt.Sprintf("%v", name)] = child
+		}
+		return flattenValue(objects, dirs, key, m)
+	case []interface{}:
+		m := make(map[string]interface{})
+		for index, child := range v {
+			m[strconv.Itoa(index)] = child
+		}
+		return flattenValue(objects, dirs, key, m)
+	case nil:
+		objects[key] = ""
+	case string:
+		objects[key] = v
+	default:
+		objects[key] = fmt.Sprintf("%v", v)
+	}
+
+	return nil
+}
+
+// encodeDocument encodes the objects below root in the given format. When envelope is not nil
+// the JSON and YAML formats include the metadata of each node.
+func encodeDocument(format string, node *etcd.Node, root string, envelope *documentEnvelope) ([]byte, error) {
+	var data interface{} = nodeToDocument(node)
+	if envelope != nil {
+		data = envelope
+	}
+
+	switch format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(data)
+	}
+
+	objects := make(map[string]string)
+	flattenNode(objects, node, root)
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buffer := bytes.NewBufferString("")
+	for _, key := range keys {
+		name := key
+		if format == FormatEnv {
+			name = envName(key)
+		}
+		buffer.WriteString(fmt.Sprintf("%s=%s\n", name, strconv.Quote(objects[key])))
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// decodeDocument decodes a document in the given format. The metadata is returned when the
+// document was exported with it, and is otherwise nil.
+func decodeDocument(format string, b []byte) (interface{}, map[string]nodeMeta, error) {
+	var doc interface{}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = decodeJSON(b, &doc)
+	case FormatYAML:
+		err = yaml.Unmarshal(b, &doc)
+	default:
+		doc, err = decodeLines(b)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return unwrapEnvelope(format, doc, b)
+}
+
+// unwrapEnvelope returns the data and metadata of documents exported with metadata, and
+// returns other documents unchanged.
+func unwrapEnvelope(format string, doc interface{}, b []byte) (interface{}, map[string]nodeMeta, error) {
+	var m map[string]interface{}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		m = v
+	case map[interface{}]interface{}:
+		m = make(map[string]interface{})
+		for key, value := range v {
+			m[fmt.Sprintf("%v", key)] = value
+		}
+	}
+	_, hasVersion := m["etcdsh"]
+	_, hasData := m["data"]
+	if !hasVersion || !hasData {
+		return doc, nil, nil
+	}
+
+	envelope := &documentEnvelope{}
+	var err error
+	if format == FormatYAML {
+		err = yaml.Unmarshal(b, envelope)
+	} else {
+		err = decodeJSON(b, envelope)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return envelope.Data, envelope.Meta, nil
+}
+
+// decodeJSON decodes a JSON document into v. Numbers are decoded as json.Number rather than
+// float64, so they are imported exactly as they were written.
+func decodeJSON(b []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+// decodeLines decodes documents in the env and flat formats, where each line holds a name and
+// a value separated by "=". Values may be quoted. Blank lines and lines starting with "#"
+// are ignored.
+func decodeLines(b []byte) (interface{}, error) {
+	doc := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("line %d: expected name=value", number)
+		}
+		value := strings.TrimSpace(parts[1])
+		if strings.HasPrefix(value, "\"") {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", number, err)
+			}
+			value = unquoted
+		} else if len(value) > 1 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+			value = value[1 : len(value)-1]
+		}
+
+		err := setDocumentValue(doc, strings.Trim(strings.TrimSpace(parts[0]), "/"), value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", number, err)
+		}
+	}
+
+	return doc, scanner.Err()
+}
+
+// setDocumentValue sets the value in the document at the given slash separated path, creating
+// maps as needed.
+func setDocumentValue(doc map[string]interface{}, key, value string) error {
+	parts := strings.Split(key, "/")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := doc[part]
+		if !ok {
+			child = make(map[string]interface{})
+			doc[part] = child
+		}
+		m, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is both an object and a key", key)
+		}
+		doc = m
+	}
+
+	name := parts[len(parts)-1]
+	if _, ok := doc[name].(map[string]interface{}); ok {
+		return fmt.Errorf("%s is both an object and a key", key)
+	}
+	doc[name] = value
+
+	return nil
+}
+
+// envName converts a key into an environment variable name, eg "apps/web-1/url" becomes
+// "APPS_WEB_1_URL".
+func envName(key string) string {
+	name := []rune(strings.ToUpper(key))
+	for index, ch := range name {
+		if (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') {
+			name[index] = '_'
+		}
+	}
+
+	return string(name)
+}
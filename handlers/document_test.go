@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func testNode() *etcd.Node {
+	return &etcd.Node{
+		Key: "/apps",
+		Dir: true,
+		Nodes: etcd.Nodes{
+			&etcd.Node{Key: "/apps/web", Value: "http://example.com"},
+			&etcd.Node{
+				Key: "/apps/db",
+				Dir: true,
+				Nodes: etcd.Nodes{
+					&etcd.Node{Key: "/apps/db/host", Value: "db1"},
+				},
+			},
+		},
+	}
+}
+
+func TestNodeToDocument(t *testing.T) {
+	expected := map[string]interface{}{
+		"web": "http://example.com",
+		"db": map[string]interface{}{
+			"host": "db1",
+		},
+	}
+	actual := nodeToDocument(testNode())
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("nodeToDocument() = %v, want %v.", actual, expected)
+	}
+}
+
+func TestFlattenDocument(t *testing.T) {
+	doc := map[string]interface{}{
+		"web":   "http://example.com",
+		"port":  float64(8080),
+		"empty": map[string]interface{}{},
+		"db": map[interface{}]interface{}{
+			"host": "db1",
+			"tls":  true,
+		},
+	}
+	objects, dirs, err := flattenDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"web":     "http://example.com",
+		"port":    "8080",
+		"db/host": "db1",
+		"db/tls":  "true",
+	}
+	if !reflect.DeepEqual(expected, objects) {
+		t.Errorf("flattenDocument() objects = %v, want %v.", objects, expected)
+	}
+	if !reflect.DeepEqual([]string{"empty"}, dirs) {
+		t.Errorf("flattenDocument() dirs = %v, want [empty].", dirs)
+	}
+
+	_, _, err = flattenDocument("value")
+	if err == nil {
+		t.Error("flattenDocument('value') expected error, got nil.")
+	}
+}
+
+func TestEncodeDocumentEnv(t *testing.T) {
+	b, err := encodeDocument(FormatEnv, testNode(), "/apps", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "DB_HOST=\"db1\"\nWEB=\"http://example.com\"\n"
+	if expected != string(b) {
+		t.Errorf("encodeDocument(env) = %q, want %q.", string(b), expected)
+	}
+}
+
+func TestDecodeLines(t *testing.T) {
+	b, err := encodeDocument(FormatFlat, testNode(), "/apps", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, meta, err := decodeDocument(FormatFlat, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta != nil {
+		t.Errorf("decodeDocument(flat) meta = %v, want nil.", meta)
+	}
+	if !reflect.DeepEqual(nodeToDocument(testNode()), doc) {
+		t.Errorf("decodeDocument(flat) = %v, want %v.", doc, nodeToDocument(testNode()))
+	}
+
+	_, _, err = decodeDocument(FormatEnv, []byte("NOT A PAIR\n"))
+	if err == nil {
+		t.Error("decodeDocument() expected error, got nil.")
+	}
+}
+
+func TestDecodeDocumentNumbers(t *testing.T) {
+	doc, _, err := decodeDocument(FormatJSON, []byte(`{"n": 1000000, "big": 12345678901, "pi": 3.14}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, _, err := flattenDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"n":   "1000000",
+		"big": "12345678901",
+		"pi":  "3.14",
+	}
+	if !reflect.DeepEqual(expected, objects) {
+		t.Errorf("flattenDocument() = %v, want %v.", objects, expected)
+	}
+}
+
+func TestDecodeDocumentEnvelope(t *testing.T) {
+	resp := &etcd.Response{Node: testNode(), EtcdIndex: 12}
+	b, err := encodeDocument(FormatJSON, resp.Node, "/apps", newDocumentEnvelope(resp, "/apps"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, meta, err := decodeDocument(FormatJSON, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nodeToDocument(testNode()), doc) {
+		t.Errorf("decodeDocument(json) = %v, want %v.", doc, nodeToDocument(testNode()))
+	}
+	if !meta["db"].Dir || meta["db/host"].Dir {
+		t.Errorf("decodeDocument(json) meta = %v, want db to be a key.", meta)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	actual := envName("apps/web-1/url")
+	if "APPS_WEB_1_URL" != actual {
+		t.Errorf("envName() = %v, want APPS_WEB_1_URL.", actual)
+	}
+}
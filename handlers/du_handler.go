@@ -0,0 +1,221 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the du command.
+type DuOptions struct {
+	PrintHelp     bool
+	Summarize     bool
+	HumanReadable bool
+	Depth         int
+	SortBySize    bool
+}
+
+// The usage of a single key reported by the "du" command.
+type duUsage struct {
+	Key     string
+	Keys    int
+	Objects int
+	Bytes   int64
+	depth   int
+}
+
+// DuHandler handles the "du" command.
+type DuHandler struct {
+	controller *Controller
+}
+
+// NewDuHandler returns a new DuHandler instance.
+func NewDuHandler(controller *Controller) *DuHandler {
+	return &DuHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *DuHandler) Command() string {
+	return "du"
+}
+
+// Validate returns whether the user input is valid.
+func (h *DuHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *DuHandler) Syntax() string {
+	return "du [options] <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *DuHandler) Description() string {
+	return "Displays the number of keys, objects and value bytes below each key"
+}
+
+// Handles the "du" command.
+func (h *DuHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	dir := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Get(dir, false, true)
+	if err != nil {
+		return "", err
+	}
+	if resp.Node.Key == "" {
+		resp.Node.Key = dir
+	}
+
+	usages := []*duUsage{}
+	total := diskUsage(resp.Node, 0, &usages)
+	if !resp.Node.Dir {
+		usages = append(usages, total)
+	}
+	if opts.Summarize {
+		usages = usages[len(usages)-1:]
+	} else if opts.Depth >= 0 {
+		filtered := []*duUsage{}
+		for _, u := range usages {
+			if u.depth <= opts.Depth {
+				filtered = append(filtered, u)
+			}
+		}
+		usages = filtered
+	}
+	if opts.SortBySize {
+		sort.Stable(sort.Reverse(duUsagesBySize(usages)))
+	}
+
+	return formatDiskUsage(usages, opts.HumanReadable), nil
+}
+
+// formatDiskUsage formats the usages as columns below a header, using human readable sizes
+// when human is true.
+func formatDiskUsage(usages []*duUsage, human bool) string {
+	output := bytes.NewBufferString("")
+	output.WriteString(fmt.Sprintf("%8s %6s %7s  %s\n", "SIZE", "KEYS", "OBJECTS", "PATH"))
+	for _, u := range usages {
+		size := fmt.Sprintf("%d", u.Bytes)
+		if human {
+			size = humanBytes(u.Bytes)
+		}
+		output.WriteString(fmt.Sprintf("%8s %6d %7d  %s\n", size, u.Keys, u.Objects, u.Key))
+	}
+
+	return output.String()
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *DuHandler) setupOptions(args []string) (*DuOptions, []string, error) {
+	opts := &DuOptions{}
+	flags := flag.NewFlagSet("du_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "help", false, "Show the command help")
+	flags.BoolVar(&opts.Summarize, "s", false, "Display only a total for the path")
+	flags.BoolVar(&opts.HumanReadable, "h", false, "Print sizes in human readable format, eg 1K, 234M")
+	flags.IntVar(&opts.Depth, "d", -1, "Display keys only this many levels below the path")
+	flags.BoolVar(&opts.SortBySize, "S", false, "Sort by size, largest first")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	return opts, args, nil
+}
+
+// diskUsage appends the usage of node, and of every key below it, to usages. Keys are
+// appended after the keys below them, like the du shell command. Returns the usage of node.
+func diskUsage(node *etcd.Node, depth int, usages *[]*duUsage) *duUsage {
+	usage := &duUsage{Key: node.Key, depth: depth}
+	if !node.Dir {
+		usage.Objects = 1
+		usage.Bytes = int64(len(node.Value))
+		return usage
+	}
+
+	for _, n := range node.Nodes {
+		child := diskUsage(n, depth+1, usages)
+		usage.Keys += child.Keys
+		usage.Objects += child.Objects
+		usage.Bytes += child.Bytes
+		if n.Dir {
+			usage.Keys++
+		}
+	}
+	*usages = append(*usages, usage)
+
+	return usage
+}
+
+// humanBytes formats a number of bytes using the largest unit which keeps the number above 1,
+// eg 1.5K or 234M.
+func humanBytes(b int64) string {
+	units := []string{"K", "M", "G", "T"}
+	if b < 1024 {
+		return fmt.Sprintf("%d", b)
+	}
+
+	size := float64(b)
+	unit := ""
+	for _, u := range units {
+		size /= 1024
+		unit = u
+		if size < 1024 {
+			break
+		}
+	}
+	if size < 10 {
+		return fmt.Sprintf("%.1f%s", size, unit)
+	}
+
+	return fmt.Sprintf("%.0f%s", size, unit)
+}
+
+// duUsagesBySize sorts usages by the number of value bytes.
+type duUsagesBySize []*duUsage
+
+func (us duUsagesBySize) Len() int           { return len(us) }
+func (us duUsagesBySize) Less(i, j int) bool { return us[i].Bytes < us[j].Bytes }
+func (us duUsagesBySize) Swap(i, j int)      { us[i], us[j] = us[j], us[i] }
@@ -0,0 +1,44 @@
+package handlers
+
+import "testing"
+
+func TestHumanBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                      "0",
+		1023:                   "1023",
+		1536:                   "1.5K",
+		20 * 1024:              "20K",
+		234 * 1024 * 1024:      "234M",
+		3 * 1024 * 1024 * 1024: "3.0G",
+	}
+	for in, expected := range tests {
+		actual := humanBytes(in)
+		if expected != actual {
+			t.Errorf("humanBytes(%d) = %v, want %v.", in, actual, expected)
+		}
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	usages := []*duUsage{}
+	total := diskUsage(testNode(), 0, &usages)
+	if total.Keys != 1 || total.Objects != 2 || total.Bytes != 21 {
+		t.Errorf("diskUsage() = %d keys, %d objects, %d bytes, want 1, 2, 21.", total.Keys, total.Objects, total.Bytes)
+	}
+	if len(usages) != 2 || usages[0].Key != "/apps/db" || usages[1] != total {
+		t.Errorf("diskUsage() usages = %v, want /apps/db then /apps.", usages)
+	}
+}
+
+func TestFormatDiskUsage(t *testing.T) {
+	usages := []*duUsage{}
+	diskUsage(testNode(), 0, &usages)
+	expected := "" +
+		"    SIZE   KEYS OBJECTS  PATH\n" +
+		"       3      0       1  /apps/db\n" +
+		"      21      1       2  /apps\n"
+	actual := formatDiskUsage(usages, false)
+	if expected != actual {
+		t.Errorf("formatDiskUsage() = %q, want %q.", actual, expected)
+	}
+}
@@ -0,0 +1,239 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Editor used when neither $VISUAL nor $EDITOR are set.
+const DefaultEditor = "vi"
+
+// EditHandler handles the "edit" command.
+type EditHandler struct {
+	controller *Controller
+}
+
+// NewEditHandler returns a new EditHandler instance.
+func NewEditHandler(controller *Controller) *EditHandler {
+	return &EditHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *EditHandler) Command() string {
+	return "edit"
+}
+
+// Validate returns whether the user input is valid.
+func (h *EditHandler) Validate(i *Input) bool {
+	return len(i.Args) == 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *EditHandler) Syntax() string {
+	return "edit <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *EditHandler) Description() string {
+	return "Edits the value of an object using $EDITOR"
+}
+
+// Handles the "edit" command, asking the user what to do when the object changed while editing.
+func (h *EditHandler) Handle(i *Input) (string, error) {
+	client := h.controller.Client()
+	key := h.controller.WorkingDir(i.Args[0])
+	resp, err := client.Get(key, false, false)
+	if err != nil {
+		return "", err
+	}
+	if resp.Node.Dir {
+		return "", fmt.Errorf("edit: %s is a key, not an object", key)
+	}
+
+	base := resp.Node
+	value := base.Value
+	editing := true
+	for {
+		if editing {
+			value, err = h.editValue(value)
+			if err != nil {
+				return "", err
+			}
+			if value == base.Value {
+				return "No changes made.\n", nil
+			}
+		}
+
+		ttl := uint64(0)
+		if base.TTL > 0 {
+			ttl = uint64(base.TTL)
+		}
+		_, err = client.CompareAndSwap(key, value, ttl, "", base.ModifiedIndex)
+		if err == nil || etcdErrorCode(err) != EcodeTestFailed {
+			return "", err
+		}
+
+		resp, err = client.Get(key, false, false)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h.controller.Stdout(), "%s was modified by another client since it was fetched:\n", key)
+		fmt.Fprintf(h.controller.Stdout(), "--- %s (index %d)\n", key, base.ModifiedIndex)
+		fmt.Fprintf(h.controller.Stdout(), "+++ %s (index %d)\n", key, resp.Node.ModifiedIndex)
+		for _, line := range diffLines(splitLines(base.Value), splitLines(resp.Node.Value)) {
+			fmt.Fprintln(h.controller.Stdout(), line)
+		}
+
+		answer, err := h.controller.Prompt("(r)e-edit, (o)verwrite or (a)bort? ")
+		if err == ErrNotInteractive {
+			return "", fmt.Errorf("edit: resolving the conflict %s, changes discarded", err)
+		}
+		if err != nil {
+			return "", err
+		}
+		base = resp.Node
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "r", "re-edit":
+			editing = true
+		case "o", "overwrite":
+			editing = false
+		default:
+			return "", errors.New("edit: aborted, changes discarded")
+		}
+	}
+}
+
+// editValue writes value to a temp file, opens the file in the user's editor, and returns
+// the saved contents.
+func (h *EditHandler) editValue(value string) (string, error) {
+	file, err := ioutil.TempFile("", "etcdsh-edit-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(value)
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = h.controller.Stdin()
+	cmd.Stdout = h.controller.Stdout()
+	cmd.Stderr = h.controller.Stderr()
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("edit: editor failed: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	// Most editors add a newline to the end of the file.
+	edited := string(b)
+	if !strings.HasSuffix(value, "\n") {
+		edited = strings.TrimSuffix(edited, "\n")
+	}
+
+	return edited, nil
+}
+
+// editorCommand returns the command used to edit values.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		editor := strings.TrimSpace(os.Getenv(name))
+		if editor != "" {
+			return editor
+		}
+	}
+
+	return DefaultEditor
+}
+
+// splitLines splits a value into lines. An empty value has no lines.
+func splitLines(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+}
+
+// diffLines compares two sets of lines, and returns the lines of b prefixed with "+" when
+// they were added, the lines of a prefixed with "-" when they were removed, and the common
+// lines prefixed with a space.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := []string{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			lines = append(lines, "-"+a[i])
+			i++
+		} else {
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"host=db1", "port=5432", "user=app"}
+	b := []string{"host=db2", "port=5432", "user=app", "ssl=true"}
+	expected := []string{"-host=db1", "+host=db2", " port=5432", " user=app", "+ssl=true"}
+	actual := diffLines(a, b)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("diffLines() = %v, want %v.", actual, expected)
+	}
+
+	actual = diffLines([]string{}, []string{"a"})
+	if !reflect.DeepEqual([]string{"+a"}, actual) {
+		t.Errorf("diffLines() = %v, want [+a].", actual)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if len(splitLines("")) != 0 {
+		t.Errorf("splitLines('') = %v, want [].", splitLines(""))
+	}
+	actual := splitLines("a\nb\n")
+	if !reflect.DeepEqual([]string{"a", "b"}, actual) {
+		t.Errorf("splitLines() = %v, want [a b].", actual)
+	}
+}
@@ -0,0 +1,134 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+)
+
+// Command line options for the export command.
+type ExportOptions struct {
+	PrintHelp bool
+	Format    string
+	File      string
+	Meta      bool
+}
+
+// ExportHandler handles the "export" command.
+type ExportHandler struct {
+	controller *Controller
+}
+
+// NewExportHandler returns a new ExportHandler instance.
+func NewExportHandler(controller *Controller) *ExportHandler {
+	return &ExportHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *ExportHandler) Command() string {
+	return "export"
+}
+
+// Validate returns whether the user input is valid.
+func (h *ExportHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *ExportHandler) Syntax() string {
+	return "export [options] <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *ExportHandler) Description() string {
+	return "Exports the objects below a key as JSON, YAML or dotenv"
+}
+
+// Handles the "export" command.
+func (h *ExportHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	root := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Get(root, true, true)
+	if err != nil {
+		return "", err
+	}
+	if !resp.Node.Dir {
+		return "", fmt.Errorf("export: %s is an object, not a key", root)
+	}
+
+	var envelope *documentEnvelope
+	if opts.Meta {
+		envelope = newDocumentEnvelope(resp, root)
+	}
+	b, err := encodeDocument(opts.Format, resp.Node, root, envelope)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.File != "" {
+		return "", ioutil.WriteFile(opts.File, b, 0644)
+	}
+	return string(b), nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *ExportHandler) setupOptions(args []string) (*ExportOptions, []string, error) {
+	opts := &ExportOptions{}
+	flags := flag.NewFlagSet("export_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Format, "format", FormatJSON, "Output format: json, yaml, env or flat")
+	flags.StringVar(&opts.File, "o", "", "Write to this local file instead of the screen")
+	flags.BoolVar(&opts.Meta, "meta", false, "Include the TTL and indexes of each node (json and yaml only)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+	if !isDocumentFormat(opts.Format) {
+		return nil, nil, fmt.Errorf("export: unknown format '%s'", opts.Format)
+	}
+	if opts.Meta && opts.Format != FormatJSON && opts.Format != FormatYAML {
+		return nil, nil, fmt.Errorf("export: -meta is not supported by the %s format", opts.Format)
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	return opts, args, nil
+}
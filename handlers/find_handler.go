@@ -0,0 +1,305 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Placeholder replaced by the matched key in the "find -exec" command.
+const FindExecPlaceholder = "{}"
+
+// Command line options for the find command.
+type FindOptions struct {
+	PrintHelp  bool
+	Name       string
+	Regex      string
+	Type       string
+	TTL        string
+	NewerIndex uint64
+	MaxDepth   int
+	Exec       []string
+
+	newer  bool
+	regex  *regexp.Regexp
+	ttlCmp int
+	ttl    int64
+}
+
+// FindHandler handles the "find" command.
+type FindHandler struct {
+	controller *Controller
+}
+
+// NewFindHandler returns a new FindHandler instance.
+func NewFindHandler(controller *Controller) *FindHandler {
+	return &FindHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *FindHandler) Command() string {
+	return "find"
+}
+
+// Validate returns whether the user input is valid.
+func (h *FindHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *FindHandler) Syntax() string {
+	return "find <path> [options] [-exec <command> {} ;]"
+}
+
+// Description returns a string that describes the command.
+func (h *FindHandler) Description() string {
+	return "Searches for keys and objects below a key"
+}
+
+// Handles the "find" command.
+func (h *FindHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	dir := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Get(dir, true, true)
+	if err != nil {
+		return "", err
+	}
+
+	matches := []string{}
+	err = h.walk(resp.Node, 0, opts, &matches)
+	if err != nil {
+		return "", err
+	}
+	if len(opts.Exec) == 0 {
+		if len(matches) == 0 {
+			return "", nil
+		}
+		return strings.Join(matches, "\n") + "\n", nil
+	}
+
+	// Like the find shell command, every match is executed even when a command fails.
+	status := StatusSuccess
+	for _, key := range matches {
+		s := h.controller.handleInput(findExecInput(opts.Exec, key))
+		if s != StatusSuccess && status == StatusSuccess {
+			status = s
+		}
+	}
+	if status != StatusSuccess {
+		return "", &StatusError{Status: status}
+	}
+
+	return "", nil
+}
+
+// walk appends the key of each node matching the options to matches.
+func (h *FindHandler) walk(node *etcd.Node, depth int, opts *FindOptions, matches *[]string) error {
+	ok, err := h.matches(node, opts)
+	if err != nil {
+		return err
+	}
+	if ok {
+		key := node.Key
+		if key == "" {
+			key = "/"
+		}
+		*matches = append(*matches, key)
+	}
+	if opts.MaxDepth >= 0 && depth >= opts.MaxDepth {
+		return nil
+	}
+	for _, n := range node.Nodes {
+		err = h.walk(n, depth+1, opts, matches)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matches returns whether the node passes every test given in the options.
+func (h *FindHandler) matches(n *etcd.Node, opts *FindOptions) (bool, error) {
+	if opts.Name != "" {
+		ok, err := path.Match(opts.Name, path.Base(n.Key))
+		if err != nil {
+			return false, fmt.Errorf("find: -name '%s': %s", opts.Name, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if opts.regex != nil && !opts.regex.MatchString(n.Key) {
+		return false, nil
+	}
+	if opts.Type == SymbolTypeKeys && !n.Dir {
+		return false, nil
+	}
+	if opts.Type == SymbolTypeObjects && n.Dir {
+		return false, nil
+	}
+	if opts.TTL != "" {
+		if n.TTL <= 0 {
+			return false, nil
+		}
+		if (opts.ttlCmp < 0 && n.TTL >= opts.ttl) ||
+			(opts.ttlCmp > 0 && n.TTL <= opts.ttl) ||
+			(opts.ttlCmp == 0 && n.TTL != opts.ttl) {
+			return false, nil
+		}
+	}
+	if opts.newer && n.ModifiedIndex <= opts.NewerIndex {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+//
+// Like the find shell command the path comes before the options, and everything following
+// -exec up to a ";" is the command executed for each match.
+func (h *FindHandler) setupOptions(args []string) (*FindOptions, []string, error) {
+	opts := &FindOptions{}
+	flags := flag.NewFlagSet("find_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Name, "name", "", "Base name matches the shell pattern")
+	flags.StringVar(&opts.Regex, "regex", "", "Full key matches the regular expression")
+	flags.StringVar(&opts.Type, "type", "", "Node is a key (k) or an object (o)")
+	flags.StringVar(&opts.TTL, "ttl", "", "TTL is less than -n, more than +n or exactly n seconds")
+	flags.Uint64Var(&opts.NewerIndex, "newer-index", 0, "Modified index is greater than n")
+	flags.IntVar(&opts.MaxDepth, "maxdepth", -1, "Descend at most n levels below the path")
+
+	dir := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		dir, args = args[0], args[1:]
+	}
+	args, opts.Exec = splitFindExec(args)
+	if opts.Exec != nil && len(opts.Exec) == 0 {
+		return nil, nil, errors.New("find: missing command for -exec")
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+	if flags.NArg() > 0 {
+		return nil, nil, fmt.Errorf("find: unexpected argument '%s'", flags.Arg(0))
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "newer-index" {
+			opts.newer = true
+		}
+	})
+
+	if opts.Regex != "" {
+		opts.regex, err = regexp.Compile("^(?:" + opts.Regex + ")$")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if opts.Type != "" && opts.Type != SymbolTypeKeys && opts.Type != SymbolTypeObjects {
+		return nil, nil, fmt.Errorf("find: unknown type '%s', use %s or %s", opts.Type, SymbolTypeKeys, SymbolTypeObjects)
+	}
+	if opts.TTL != "" {
+		opts.ttlCmp, opts.ttl, err = parseNumericTest(opts.TTL)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return opts, []string{dir}, nil
+}
+
+// splitFindExec removes the -exec command from args, and returns the args before -exec and
+// after the terminating ";" along with the command. The returned command is nil when args does
+// not contain -exec.
+func splitFindExec(args []string) ([]string, []string) {
+	for index, arg := range args {
+		if arg != "-exec" {
+			continue
+		}
+
+		rest := append([]string{}, args[:index]...)
+		exec := []string{}
+		for n, a := range args[index+1:] {
+			if a == ";" {
+				rest = append(rest, args[index+n+2:]...)
+				break
+			}
+			exec = append(exec, a)
+		}
+		return rest, exec
+	}
+
+	return args, nil
+}
+
+// findExecInput builds the input for the "find -exec" command, replacing the placeholder in
+// each argument with the matched key.
+func findExecInput(exec []string, key string) *Input {
+	in := NewInput(exec[0])
+	for _, arg := range exec[1:] {
+		in.Args = append(in.Args, strings.Replace(arg, FindExecPlaceholder, key, -1))
+	}
+
+	return in
+}
+
+// parseNumericTest parses a find style numeric argument, where "-n" means less than n, "+n"
+// means more than n, and "n" means exactly n. Returns -1, 1 or 0 for the comparison.
+func parseNumericTest(s string) (int, int64, error) {
+	cmp := 0
+	if strings.HasPrefix(s, "-") {
+		cmp, s = -1, s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		cmp, s = 1, s[1:]
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("find: invalid number '%s'", s)
+	}
+
+	return cmp, n, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFindExec(t *testing.T) {
+	args, exec := splitFindExec([]string{"-name", "*.lock", "-exec", "rm", "{}", ";"})
+	if !reflect.DeepEqual(args, []string{"-name", "*.lock"}) {
+		t.Errorf("splitFindExec() args = %v, want [-name *.lock].", args)
+	}
+	if !reflect.DeepEqual(exec, []string{"rm", "{}"}) {
+		t.Errorf("splitFindExec() exec = %v, want [rm {}].", exec)
+	}
+
+	args, exec = splitFindExec([]string{"-name", "*.lock", "-exec", "get", "{}", ";", "-type", "o"})
+	if !reflect.DeepEqual(args, []string{"-name", "*.lock", "-type", "o"}) {
+		t.Errorf("splitFindExec() args = %v, want [-name *.lock -type o].", args)
+	}
+	if !reflect.DeepEqual(exec, []string{"get", "{}"}) {
+		t.Errorf("splitFindExec() exec = %v, want [get {}].", exec)
+	}
+
+	args, exec = splitFindExec([]string{"-type", "o"})
+	if len(args) != 2 {
+		t.Errorf("splitFindExec() args = %v, want [-type o].", args)
+	}
+	if exec != nil {
+		t.Errorf("splitFindExec() exec = %v, want nil.", exec)
+	}
+}
+
+func TestParseNumericTest(t *testing.T) {
+	tests := []struct {
+		in  string
+		cmp int
+		n   int64
+	}{
+		{"-30", -1, 30},
+		{"+30", 1, 30},
+		{"30", 0, 30},
+	}
+	for _, test := range tests {
+		cmp, n, err := parseNumericTest(test.in)
+		if err != nil {
+			t.Error(err)
+		} else if cmp != test.cmp || n != test.n {
+			t.Errorf("parseNumericTest(%q) = %d, %d, want %d, %d.", test.in, cmp, n, test.cmp, test.n)
+		}
+	}
+
+	_, _, err := parseNumericTest("abc")
+	if err == nil {
+		t.Error("parseNumericTest('abc') expected error, got nil.")
+	}
+}
+
+func TestFindExecInput(t *testing.T) {
+	in := findExecInput([]string{"get", "{}"}, "/apps/web")
+	if in.Cmd != "get" || !reflect.DeepEqual(in.Args, []string{"/apps/web"}) {
+		t.Errorf("findExecInput() = %v %v, want get [/apps/web].", in.Cmd, in.Args)
+	}
+}
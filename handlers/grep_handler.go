@@ -0,0 +1,178 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/headzoo/etcdsh/env"
+)
+
+// Color used to highlight the matched portion of a value.
+const DefaultColorMatch = "01;31"
+
+// Command line options for the grep command.
+type GrepOptions struct {
+	PrintHelp  bool
+	Recursive  bool
+	IgnoreCase bool
+	FilesOnly  bool
+	LineNumber bool
+	Invert     bool
+}
+
+// GrepHandler handles the "grep" command.
+type GrepHandler struct {
+	controller *Controller
+	use_colors bool
+}
+
+// NewGrepHandler returns a new GrepHandler instance.
+func NewGrepHandler(controller *Controller) *GrepHandler {
+	h := &GrepHandler{
+		controller: controller,
+	}
+	_, h.use_colors = outputColors(controller.Config())
+
+	return h
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *GrepHandler) Command() string {
+	return "grep"
+}
+
+// Validate returns whether the user input is valid.
+func (h *GrepHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *GrepHandler) Syntax() string {
+	return "grep [options] <pattern> <path>..."
+}
+
+// Description returns a string that describes the command.
+func (h *GrepHandler) Description() string {
+	return "Searches the values of objects for a pattern"
+}
+
+// Handles the "grep" command.
+func (h *GrepHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	pattern := args[0]
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	paths := args[1:]
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	output := bytes.NewBufferString("")
+	for _, p := range paths {
+		key := h.controller.WorkingDir(p)
+		resp, err := h.controller.Client().Get(key, true, opts.Recursive)
+		if err != nil {
+			return output.String(), err
+		}
+		if resp.Node.Dir && !opts.Recursive {
+			return output.String(), fmt.Errorf("grep: %s is a key, use -r to search below it", key)
+		}
+		h.grepNode(output, resp.Node, re, opts)
+	}
+
+	return output.String(), nil
+}
+
+// grepNode writes the matching lines of node, and every object below it, to the output.
+func (h *GrepHandler) grepNode(output *bytes.Buffer, node *etcd.Node, re *regexp.Regexp, opts *GrepOptions) {
+	if node.Dir {
+		for _, n := range node.Nodes {
+			h.grepNode(output, n, re, opts)
+		}
+		return
+	}
+
+	for index, line := range strings.Split(node.Value, "\n") {
+		if re.MatchString(line) == opts.Invert {
+			continue
+		}
+		if opts.FilesOnly {
+			output.WriteString(node.Key + "\n")
+			return
+		}
+
+		if h.use_colors && !opts.Invert {
+			line = re.ReplaceAllStringFunc(line, func(m string) string {
+				return env.ColorPrefixCode(DefaultColorMatch) + m + env.ColorPostfixCode()
+			})
+		}
+		if opts.LineNumber {
+			output.WriteString(fmt.Sprintf("%s:%d:%s\n", node.Key, index+1, line))
+		} else {
+			output.WriteString(fmt.Sprintf("%s:%s\n", node.Key, line))
+		}
+	}
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *GrepHandler) setupOptions(args []string) (*GrepOptions, []string, error) {
+	opts := &GrepOptions{}
+	flags := flag.NewFlagSet("grep_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Recursive, "r", false, "Search every object below the given keys")
+	flags.BoolVar(&opts.IgnoreCase, "i", false, "Ignore case distinctions")
+	flags.BoolVar(&opts.FilesOnly, "l", false, "Only display the names of matching objects")
+	flags.BoolVar(&opts.LineNumber, "n", false, "Prefix each line with its line number")
+	flags.BoolVar(&opts.Invert, "v", false, "Select non-matching lines")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) == 0 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
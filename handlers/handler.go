@@ -27,6 +27,24 @@ package handlers
 import (
 	"flag"
 	"fmt"
+	"io"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Error codes returned by the etcd server.
+//
+// Handlers which read a value and write it back, such as edit, mv, ttl, import, pop and lock,
+// write with a compare-and-swap or compare-and-delete against the index they read, so a value
+// changed by another client in the meantime is never replaced or removed. EcodeTestFailed
+// is returned when the compare fails.
+const (
+	EcodeKeyNotFound = 100
+	EcodeTestFailed  = 101
+	EcodeNotFile     = 102
+	EcodeNotDir      = 104
+	EcodeNodeExist   = 105
+	EcodeDirNotEmpty = 108
 )
 
 // Handler types are called when a command is given by the user.
@@ -38,16 +56,67 @@ type Handler interface {
 	Description() string
 }
 
+// Exit statuses of commands.
+const (
+	StatusSuccess = 0
+	StatusFailure = 1
+
+	// Returned by the health command when only a majority of the cluster is healthy, or when
+	// a majority is not.
+	StatusClusterDegraded    = 1
+	StatusClusterUnavailable = 2
+
+	// Returned by the diff command when the comparison could not be made. Differences are
+	// reported with StatusFailure.
+	StatusDiffTrouble = 2
+)
+
 // Represents a map of Handler instances
 type HandlerMap map[string]Handler
 
+// StatusError is returned by handlers which need to exit with a specific status. The message
+// is displayed when it is not empty.
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+// Error returns the error message.
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
 // printCommandHelp is used by handlers to display command help.
-func printCommandHelp(handler Handler, flags *flag.FlagSet) {
-	fmt.Println("SYNTAX")
-	fmt.Println("\t" + handler.Syntax())
-	fmt.Println("")
-	fmt.Println("OPTIONS:")
+func printCommandHelp(w io.Writer, handler Handler, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "SYNTAX")
+	fmt.Fprintln(w, "\t"+handler.Syntax())
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "OPTIONS:")
 	flags.VisitAll(func(f *flag.Flag) {
-		fmt.Printf("\t-%-10s%s\n", f.Name, f.Usage)
+		fmt.Fprintf(w, "\t-%-10s%s\n", f.Name, f.Usage)
 	})
 }
+
+// usageError returns the error for a command whose help was displayed by setupOptions: nil when
+// the help was asked for with -h, and otherwise a failure status because the command was used
+// incorrectly.
+func usageError(help bool) error {
+	if help {
+		return nil
+	}
+
+	return &StatusError{Status: StatusFailure}
+}
+
+// etcdErrorCode returns the error code of an error returned by the etcd server, or 0 when err
+// did not come from the server.
+func etcdErrorCode(err error) int {
+	switch e := err.(type) {
+	case *etcd.EtcdError:
+		return e.ErrorCode
+	case etcd.EtcdError:
+		return e.ErrorCode
+	}
+
+	return 0
+}
@@ -0,0 +1,202 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Overall verdicts of the "health" command.
+const (
+	HealthHealthy     = "healthy"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+)
+
+// The result of probing a single member.
+type memberHealth struct {
+	Machine   string
+	Reachable bool
+	Latency   time.Duration
+	Version   string
+	Leader    string
+	Err       error
+}
+
+// HealthHandler handles the "health" command.
+type HealthHandler struct {
+	controller *Controller
+}
+
+// NewHealthHandler returns a new HealthHandler instance.
+func NewHealthHandler(controller *Controller) *HealthHandler {
+	return &HealthHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *HealthHandler) Command() string {
+	return "health"
+}
+
+// Validate returns whether the user input is valid.
+func (h *HealthHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *HealthHandler) Syntax() string {
+	return "health"
+}
+
+// Description returns a string that describes the command.
+func (h *HealthHandler) Description() string {
+	return "Checks the health of every member of the cluster"
+}
+
+// Handles the "health" command.
+//
+// Each member is asked for its version, its view of the leader, and a quorum read. The
+// cluster is healthy when every member responds and agrees on the leader, degraded when a
+// majority of members respond and agree, and otherwise unavailable. The command exits with
+// status 1 when the cluster is degraded, and 2 when it is unavailable.
+func (h *HealthHandler) Handle(i *Input) (string, error) {
+	machines := h.controller.clusterMachines()
+
+	results := make([]*memberHealth, len(machines))
+	var wg sync.WaitGroup
+	for index, machine := range machines {
+		wg.Add(1)
+		go func(index int, machine string) {
+			defer wg.Done()
+			results[index] = probeMember(machine)
+		}(index, machine)
+	}
+	wg.Wait()
+
+	output := bytes.NewBufferString("")
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "MEMBER\tREACHABLE\tLATENCY\tVERSION\tLEADER\tERROR")
+	for _, r := range results {
+		errMessage := ""
+		if r.Err != nil {
+			errMessage = r.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\t%s\n", r.Machine, r.Reachable, r.Latency, r.Version, r.Leader, errMessage)
+	}
+	w.Flush()
+
+	verdict := healthVerdict(results)
+	output.WriteString(fmt.Sprintf("\nCluster is %s.\n", verdict))
+	switch verdict {
+	case HealthDegraded:
+		return output.String(), &StatusError{Status: StatusClusterDegraded}
+	case HealthUnavailable:
+		return output.String(), &StatusError{Status: StatusClusterUnavailable}
+	}
+
+	return output.String(), nil
+}
+
+// probeMember checks a single member of the cluster.
+func probeMember(machine string) *memberHealth {
+	result := &memberHealth{Machine: machine}
+
+	body, err := machineGet(machine, "/version")
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Reachable = true
+	result.Version = parseVersion(body)
+
+	stats := &selfStats{}
+	body, err = machineGet(machine, "/v2/stats/self")
+	if err == nil {
+		err = json.Unmarshal(body, stats)
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Leader = stats.LeaderInfo.Leader
+
+	start := time.Now()
+	_, err = machineGet(machine, "/v2/keys/?quorum=true")
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("quorum read failed: %s", err)
+	}
+
+	return result
+}
+
+// parseVersion returns the server version from the body of a /version response, which is
+// either JSON or plain text depending on the etcd version.
+func parseVersion(body []byte) string {
+	version := struct {
+		Server string `json:"etcdserver"`
+	}{}
+	err := json.Unmarshal(body, &version)
+	if err == nil && version.Server != "" {
+		return version.Server
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(string(body)), "etcd ")
+}
+
+// healthVerdict returns the overall health of the cluster from the results of each member.
+func healthVerdict(results []*memberHealth) string {
+	leaders := make(map[string]int)
+	for _, r := range results {
+		if r.Err == nil && r.Leader != "" {
+			leaders[r.Leader]++
+		}
+	}
+
+	// The number of healthy members which agree on the most common leader.
+	agreed := 0
+	for _, count := range leaders {
+		if count > agreed {
+			agreed = count
+		}
+	}
+
+	switch {
+	case len(results) > 0 && agreed == len(results):
+		return HealthHealthy
+	case agreed > len(results)/2:
+		return HealthDegraded
+	}
+
+	return HealthUnavailable
+}
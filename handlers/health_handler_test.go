@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHealthVerdict(t *testing.T) {
+	healthy := &memberHealth{Reachable: true, Leader: "a"}
+	other := &memberHealth{Reachable: true, Leader: "b"}
+	down := &memberHealth{Err: errors.New("connection refused")}
+
+	tests := []struct {
+		results  []*memberHealth
+		expected string
+	}{
+		{[]*memberHealth{healthy, healthy, healthy}, HealthHealthy},
+		{[]*memberHealth{healthy, healthy, down}, HealthDegraded},
+		{[]*memberHealth{healthy, other, healthy}, HealthDegraded},
+		{[]*memberHealth{healthy, down, down}, HealthUnavailable},
+		{[]*memberHealth{}, HealthUnavailable},
+	}
+	for index, test := range tests {
+		actual := healthVerdict(test.results)
+		if test.expected != actual {
+			t.Errorf("healthVerdict() #%d = %v, want %v.", index, actual, test.expected)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	actual := parseVersion([]byte(`{"etcdserver":"2.1.0","etcdcluster":"2.1.0"}`))
+	if "2.1.0" != actual {
+		t.Errorf("parseVersion() = %v, want 2.1.0.", actual)
+	}
+	actual = parseVersion([]byte("etcd 2.0.0\n"))
+	if "2.0.0" != actual {
+		t.Errorf("parseVersion() = %v, want 2.0.0.", actual)
+	}
+}
@@ -0,0 +1,214 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bobappleyard/readline"
+)
+
+// History stores the commands entered by the user.
+//
+// Readline keeps its own copy of the history for the arrow keys, so commands added here are
+// also added to readline.
+type History struct {
+	lines []string
+}
+
+// NewHistory returns a new History instance.
+func NewHistory() *History {
+	return &History{
+		lines: []string{},
+	}
+}
+
+// Add appends a command to the history.
+func (h *History) Add(line string) {
+	h.lines = append(h.lines, line)
+	readline.AddHistory(line)
+}
+
+// Lines returns every command in the history, oldest first. The command at index 0 is
+// numbered 1 by the history command and history expansion.
+func (h *History) Lines() []string {
+	return h.lines
+}
+
+// Clear removes every command from the history.
+func (h *History) Clear() {
+	h.lines = []string{}
+	readline.ClearHistory()
+}
+
+// Load adds the commands saved in the file at path to the history. A missing file is not an
+// error.
+func (h *History) Load(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if scanner.Text() != "" {
+			h.Add(scanner.Text())
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Save writes the last size commands in the history to the file at path.
+func (h *History) Save(path string, size int) error {
+	lines := h.lines
+	if size < 0 {
+		size = 0
+	}
+	if len(lines) > size {
+		lines = lines[len(lines)-size:]
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		// Multi-line commands are saved on one line so they load as a single command.
+		writer.WriteString(strings.Replace(line, "\n", " ", -1) + "\n")
+	}
+	err = writer.Flush()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Expand replaces the history events in line the way bash does, and returns the expanded line
+// and whether anything was replaced. The supported events are "!!" for the last command, "!n"
+// for command number n, "!-n" for the nth previous command, and "!prefix" for the most recent
+// command starting with prefix. Events are not expanded inside single quotes, or when the "!"
+// is escaped with a backslash or followed by whitespace or one of =(;|&<>.
+func (h *History) Expand(line string) (string, bool, error) {
+	runes := []rune(line)
+	expanded := make([]rune, 0, len(runes))
+	replaced := false
+	quoted := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '\\' && !quoted && i+1 < len(runes):
+			expanded = append(expanded, r, runes[i+1])
+			i++
+			continue
+		case r == '!' && !quoted && i+1 < len(runes) && !isHistoryEventEnd(runes[i+1]):
+			event := historyEvent(runes[i+1:])
+			if event == "" {
+				break
+			}
+			command, err := h.event(event)
+			if err != nil {
+				return line, false, err
+			}
+			expanded = append(expanded, []rune(command)...)
+			replaced = true
+			i += len([]rune(event))
+			continue
+		}
+		expanded = append(expanded, r)
+	}
+
+	return string(expanded), replaced, nil
+}
+
+// event returns the command matching a history event, without the leading "!".
+func (h *History) event(event string) (string, error) {
+	number := event
+	if event == "!" {
+		number = "-1"
+	}
+
+	if n, err := strconv.Atoi(number); err == nil {
+		if n < 0 {
+			n = len(h.lines) + n + 1
+		}
+		if n >= 1 && n <= len(h.lines) {
+			return h.lines[n-1], nil
+		}
+	} else {
+		for i := len(h.lines) - 1; i >= 0; i-- {
+			if strings.HasPrefix(h.lines[i], event) {
+				return h.lines[i], nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("!%s: event not found", event)
+}
+
+// historyEvent returns the history event at the start of runes, which follow a "!".
+func historyEvent(runes []rune) string {
+	if runes[0] == '!' {
+		return "!"
+	}
+
+	end := 0
+	if runes[0] == '-' {
+		end = 1
+	}
+	if end < len(runes) && unicode.IsDigit(runes[end]) {
+		for end < len(runes) && unicode.IsDigit(runes[end]) {
+			end++
+		}
+		return string(runes[:end])
+	}
+
+	end = 0
+	for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`'";|&<>`, runes[end]) {
+		end++
+	}
+
+	return string(runes[:end])
+}
+
+// isHistoryEventEnd returns whether a "!" followed by r is left alone by history expansion.
+func isHistoryEventEnd(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`=('";|&<>`, r)
+}
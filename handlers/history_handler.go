@@ -0,0 +1,125 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// Command line options for the history command.
+type HistoryOptions struct {
+	PrintHelp bool
+	Clear     bool
+}
+
+// HistoryHandler handles the "history" command.
+type HistoryHandler struct {
+	controller *Controller
+}
+
+// NewHistoryHandler returns a new HistoryHandler instance.
+func NewHistoryHandler(controller *Controller) *HistoryHandler {
+	return &HistoryHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *HistoryHandler) Command() string {
+	return "history"
+}
+
+// Validate returns whether the user input is valid.
+func (h *HistoryHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *HistoryHandler) Syntax() string {
+	return "history [options] [n]"
+}
+
+// Description returns a string that describes the command.
+func (h *HistoryHandler) Description() string {
+	return "Displays the command history"
+}
+
+// Handles the "history" command.
+func (h *HistoryHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+	history := h.controller.History()
+	if opts.Clear {
+		history.Clear()
+		return "", nil
+	}
+
+	lines := history.Lines()
+	start := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return "", errors.New("history: numeric argument required")
+		}
+		if n < len(lines) {
+			start = len(lines) - n
+		}
+	}
+
+	output := bytes.NewBufferString("")
+	for n := start; n < len(lines); n++ {
+		output.WriteString(fmt.Sprintf("%5d  %s\n", n+1, lines[n]))
+	}
+
+	return output.String(), nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *HistoryHandler) setupOptions(args []string) (*HistoryOptions, []string, error) {
+	opts := &HistoryOptions{}
+	flags := flag.NewFlagSet("history_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Clear, "c", false, "Clear the history")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+	if flags.NArg() > 1 {
+		return nil, nil, errors.New("history: too many arguments")
+	}
+
+	return opts, flags.Args(), nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testHistory() *History {
+	history := NewHistory()
+	history.Add("ls /apps")
+	history.Add("get /apps/version")
+	history.Add("cd /domains")
+	return history
+}
+
+func TestHistoryExpand(t *testing.T) {
+	tests := map[string]string{
+		"!!":               "cd /domains",
+		"!1":               "ls /apps",
+		"!-2":              "get /apps/version",
+		"!get":             "get /apps/version",
+		"!l -l":            "ls /apps -l",
+		"echo !!":          "echo cd /domains",
+		"set /a 'hi!!'":    "set /a 'hi!!'",
+		"set /a \\!!":      "set /a \\!!",
+		"set /a hi!":       "set /a hi!",
+		"set /a hi! there": "set /a hi! there",
+		"ls /apps":         "ls /apps",
+		"set /a hi!;ls":    "set /a hi!;ls",
+		"get /a!|cat":      "get /a!|cat",
+		"set /a x!>out":    "set /a x!>out",
+		"set /a x!<in":     "set /a x!<in",
+		"ls!&":             "ls!&",
+	}
+	history := testHistory()
+	for in, expected := range tests {
+		actual, _, err := history.Expand(in)
+		if err != nil {
+			t.Errorf("Expand(%q) returned error %s.", in, err)
+		} else if expected != actual {
+			t.Errorf("Expand(%q) = %q, want %q.", in, actual, expected)
+		}
+	}
+
+	for _, in := range []string{"!4", "!-4", "!rm"} {
+		_, _, err := history.Expand(in)
+		if err == nil {
+			t.Errorf("Expand(%q) did not return an error.", in)
+		}
+	}
+}
+
+func TestHistorySaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "history")
+	err = testHistory().Save(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history := NewHistory()
+	err = history.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"get /apps/version", "cd /domains"}
+	if !reflect.DeepEqual(history.Lines(), expected) {
+		t.Errorf("Load() = %v, want %v.", history.Lines(), expected)
+	}
+
+	err = NewHistory().Load(filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Errorf("Load() of a missing file returned error %s.", err)
+	}
+}
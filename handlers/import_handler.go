@@ -0,0 +1,363 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Actions which may appear in an import plan.
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportDelete = "delete"
+	ImportMkdir  = "mkdir"
+	ImportRmdir  = "rmdir"
+	ImportSkip   = "skip"
+)
+
+// Symbols displayed before each action in an import plan.
+var importSymbols = map[string]string{
+	ImportCreate: "+",
+	ImportUpdate: "~",
+	ImportDelete: "-",
+	ImportMkdir:  "+",
+	ImportRmdir:  "-",
+	ImportSkip:   "!",
+}
+
+// Command line options for the import command.
+type ImportOptions struct {
+	PrintHelp bool
+	Format    string
+	DryRun    bool
+	Prune     bool
+	Overwrite bool
+}
+
+// A single change made by the import command.
+type importChange struct {
+	Action string
+	Key    string
+	Value  string
+	TTL    uint64
+	Index  uint64
+	Reason string
+}
+
+// ImportHandler handles the "import" command.
+type ImportHandler struct {
+	controller *Controller
+}
+
+// NewImportHandler returns a new ImportHandler instance.
+func NewImportHandler(controller *Controller) *ImportHandler {
+	return &ImportHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *ImportHandler) Command() string {
+	return "import"
+}
+
+// Validate returns whether the user input is valid.
+func (h *ImportHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *ImportHandler) Syntax() string {
+	return "import [options] <file> <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *ImportHandler) Description() string {
+	return "Imports a JSON, YAML or dotenv file below a key"
+}
+
+// Handles the "import" command.
+//
+// The changes needed to make the key match the file are displayed before they are made.
+// Existing objects with a different value are only updated with -overwrite, and objects and
+// keys missing from the file are only removed with -prune.
+func (h *ImportHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = formatFromFilename(args[0])
+	}
+	b, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return "", err
+	}
+	doc, meta, err := decodeDocument(format, b)
+	if err != nil {
+		return "", fmt.Errorf("import: cannot read %s: %s", args[0], err)
+	}
+	objects, dirs, err := flattenDocument(doc)
+	if err != nil {
+		return "", fmt.Errorf("import: cannot read %s: %s", args[0], err)
+	}
+
+	root := h.controller.WorkingDir(args[1])
+	var current *etcd.Node
+	resp, err := h.controller.Client().Get(root, true, true)
+	if err == nil {
+		current = resp.Node
+	} else if etcdErrorCode(err) != EcodeKeyNotFound {
+		return "", err
+	}
+	if current != nil && !current.Dir {
+		return "", fmt.Errorf("import: %s is an object, not a key", root)
+	}
+
+	changes := importPlan(root, current, objects, dirs, meta, opts)
+	if len(changes) == 0 {
+		return "Nothing to import.\n", nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(h.controller.Stdout(), change.String())
+	}
+	if opts.DryRun {
+		return "", nil
+	}
+
+	applied, failed := h.apply(changes)
+	h.controller.RefreshWorkingDirKeys()
+	summary := fmt.Sprintf("Applied %d changes.\n", applied)
+	if len(failed) > 0 {
+		return summary, errors.New(strings.Join(failed, "\n"))
+	}
+	return summary, nil
+}
+
+// apply makes the changes. Returns the number of changes made, and a message for each change
+// which failed.
+func (h *ImportHandler) apply(changes []*importChange) (int, []string) {
+	client := h.controller.Client()
+	applied := 0
+	failed := []string{}
+	for _, change := range changes {
+		var err error
+		switch change.Action {
+		case ImportCreate:
+			_, err = client.Create(change.Key, change.Value, change.TTL)
+		case ImportUpdate:
+			_, err = client.CompareAndSwap(change.Key, change.Value, change.TTL, "", change.Index)
+		case ImportDelete:
+			_, err = client.CompareAndDelete(change.Key, "", change.Index)
+		case ImportMkdir:
+			err = ensureDir(client, change.Key, change.TTL)
+		case ImportRmdir:
+			_, err = client.DeleteDir(change.Key)
+		default:
+			continue
+		}
+
+		if err != nil {
+			if etcdErrorCode(err) == EcodeTestFailed {
+				err = errors.New("modified by another client during the import")
+			}
+			failed = append(failed, fmt.Sprintf("import: cannot %s %s: %s", change.Action, change.Key, err))
+		} else {
+			applied++
+		}
+	}
+
+	return applied, failed
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *ImportHandler) setupOptions(args []string) (*ImportOptions, []string, error) {
+	opts := &ImportOptions{}
+	flags := flag.NewFlagSet("import_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Format, "format", "", "File format: json, yaml, env or flat (default from the file extension)")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Display the changes without making them")
+	flags.BoolVar(&opts.Prune, "prune", false, "Remove objects and keys which are not in the file")
+	flags.BoolVar(&opts.Overwrite, "overwrite", false, "Update existing objects with a different value")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) != 2 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+	if opts.Format != "" && !isDocumentFormat(opts.Format) {
+		return nil, nil, fmt.Errorf("import: unknown format '%s'", opts.Format)
+	}
+
+	return opts, args, nil
+}
+
+// String returns the change formatted for display in the plan.
+func (c *importChange) String() string {
+	s := fmt.Sprintf("%s %-6s %s", importSymbols[c.Action], c.Action, c.Key)
+	if c.Reason != "" {
+		s += " (" + c.Reason + ")"
+	}
+
+	return s
+}
+
+// importPlan returns the changes needed to import the objects and empty keys below root,
+// given the current node at root, which is nil when root does not exist. Removals are ordered
+// deepest first so keys are empty before they are removed.
+func importPlan(root string, current *etcd.Node, objects map[string]string, dirs []string, meta map[string]nodeMeta, opts *ImportOptions) []*importChange {
+	existing := make(map[string]*etcd.Node)
+	if current != nil {
+		addExistingNodes(existing, current, root)
+	}
+
+	// Every key which holds an object or empty key in the file.
+	wanted := make(map[string]bool)
+	for key := range objects {
+		for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+			wanted[dir] = true
+		}
+	}
+	for _, dir := range dirs {
+		for d := dir; d != "."; d = path.Dir(d) {
+			wanted[d] = true
+		}
+	}
+
+	// Keys which are skipped keep everything below them when pruning.
+	skipped := []string{}
+	changes := []*importChange{}
+	for key, value := range objects {
+		change := &importChange{Key: path.Join(root, key), Value: value, TTL: metaTTL(meta, key)}
+		node, ok := existing[key]
+		switch {
+		case !ok:
+			change.Action = ImportCreate
+		case node.Dir:
+			change.Action, change.Reason = ImportSkip, "is a key in etcd, but an object in the file"
+			skipped = append(skipped, key)
+		case node.Value == value:
+			continue
+		case !opts.Overwrite:
+			change.Action, change.Reason = ImportSkip, "exists with a different value, use -overwrite"
+		default:
+			change.Action, change.Index = ImportUpdate, node.ModifiedIndex
+		}
+		changes = append(changes, change)
+	}
+	for _, dir := range dirs {
+		node, ok := existing[dir]
+		if !ok {
+			changes = append(changes, &importChange{Action: ImportMkdir, Key: path.Join(root, dir), TTL: metaTTL(meta, dir)})
+		} else if !node.Dir {
+			changes = append(changes, &importChange{Action: ImportSkip, Key: path.Join(root, dir), Reason: "is an object in etcd, but a key in the file"})
+		}
+	}
+	sort.Sort(importChangesByKey(changes))
+
+	if opts.Prune {
+		removals := []*importChange{}
+		for key, node := range existing {
+			if _, ok := objects[key]; ok || wanted[key] || isBelowAny(key, skipped) {
+				continue
+			}
+			if node.Dir {
+				removals = append(removals, &importChange{Action: ImportRmdir, Key: node.Key})
+			} else {
+				removals = append(removals, &importChange{Action: ImportDelete, Key: node.Key, Index: node.ModifiedIndex})
+			}
+		}
+		sort.Sort(sort.Reverse(importChangesByKey(removals)))
+		changes = append(changes, removals...)
+	}
+
+	return changes
+}
+
+// isBelowAny returns whether key is below any of the given keys.
+func isBelowAny(key string, keys []string) bool {
+	for _, k := range keys {
+		if strings.HasPrefix(key, k+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addExistingNodes adds every node below node to existing, using keys relative to root.
+func addExistingNodes(existing map[string]*etcd.Node, node *etcd.Node, root string) {
+	for _, n := range node.Nodes {
+		existing[relativeKey(root, n.Key)] = n
+		addExistingNodes(existing, n, root)
+	}
+}
+
+// metaTTL returns the TTL of the key in the metadata, or 0 when it has none.
+func metaTTL(meta map[string]nodeMeta, key string) uint64 {
+	m, ok := meta[key]
+	if !ok || m.TTL <= 0 {
+		return 0
+	}
+
+	return uint64(m.TTL)
+}
+
+// formatFromFilename returns the document format matching the extension of the file name.
+func formatFromFilename(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".yml", ".yaml":
+		return FormatYAML
+	case ".env":
+		return FormatEnv
+	case ".txt", ".flat":
+		return FormatFlat
+	}
+
+	return FormatJSON
+}
+
+// importChangesByKey sorts import changes by key.
+type importChangesByKey []*importChange
+
+func (cs importChangesByKey) Len() int           { return len(cs) }
+func (cs importChangesByKey) Less(i, j int) bool { return cs[i].Key < cs[j].Key }
+func (cs importChangesByKey) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
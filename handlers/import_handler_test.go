@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func planActions(changes []*importChange) map[string]string {
+	actions := make(map[string]string)
+	for _, change := range changes {
+		actions[change.Key] = change.Action
+	}
+
+	return actions
+}
+
+func TestImportPlan(t *testing.T) {
+	current := &etcd.Node{
+		Key: "/apps",
+		Dir: true,
+		Nodes: etcd.Nodes{
+			&etcd.Node{Key: "/apps/web", Value: "http://example.com", ModifiedIndex: 4},
+			&etcd.Node{Key: "/apps/port", Value: "80", ModifiedIndex: 5},
+			&etcd.Node{
+				Key: "/apps/old",
+				Dir: true,
+				Nodes: etcd.Nodes{
+					&etcd.Node{Key: "/apps/old/host", Value: "db0", ModifiedIndex: 6},
+				},
+			},
+		},
+	}
+	objects := map[string]string{
+		"web":     "http://example.com",
+		"port":    "8080",
+		"db/host": "db1",
+	}
+
+	opts := &ImportOptions{}
+	actions := planActions(importPlan("/apps", current, objects, []string{"empty"}, nil, opts))
+	expected := map[string]string{
+		"/apps/port":    ImportSkip,
+		"/apps/db/host": ImportCreate,
+		"/apps/empty":   ImportMkdir,
+	}
+	if len(expected) != len(actions) {
+		t.Errorf("importPlan() = %v, want %v.", actions, expected)
+	}
+	for key, action := range expected {
+		if actions[key] != action {
+			t.Errorf("importPlan() %s = %q, want %q.", key, actions[key], action)
+		}
+	}
+
+	opts = &ImportOptions{Overwrite: true, Prune: true}
+	changes := importPlan("/apps", current, objects, nil, nil, opts)
+	actions = planActions(changes)
+	if actions["/apps/port"] != ImportUpdate {
+		t.Errorf("importPlan() /apps/port = %q, want %q.", actions["/apps/port"], ImportUpdate)
+	}
+	if actions["/apps/old/host"] != ImportDelete || actions["/apps/old"] != ImportRmdir {
+		t.Errorf("importPlan() = %v, want /apps/old pruned.", actions)
+	}
+	last := changes[len(changes)-1]
+	if last.Key != "/apps/old" {
+		t.Errorf("importPlan() last change = %s, want /apps/old.", last.Key)
+	}
+}
+
+func TestImportPlanPruneSkipped(t *testing.T) {
+	current := &etcd.Node{
+		Key: "/apps",
+		Dir: true,
+		Nodes: etcd.Nodes{
+			&etcd.Node{
+				Key: "/apps/x",
+				Dir: true,
+				Nodes: etcd.Nodes{
+					&etcd.Node{Key: "/apps/x/host", Value: "db0", ModifiedIndex: 6},
+				},
+			},
+		},
+	}
+	objects := map[string]string{"x": "value"}
+
+	opts := &ImportOptions{Prune: true}
+	actions := planActions(importPlan("/apps", current, objects, nil, nil, opts))
+	expected := map[string]string{"/apps/x": ImportSkip}
+	if len(expected) != len(actions) || actions["/apps/x"] != ImportSkip {
+		t.Errorf("importPlan() = %v, want %v.", actions, expected)
+	}
+}
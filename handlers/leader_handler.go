@@ -0,0 +1,92 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LeaderHandler handles the "leader" command.
+type LeaderHandler struct {
+	controller *Controller
+}
+
+// NewLeaderHandler returns a new LeaderHandler instance.
+func NewLeaderHandler(controller *Controller) *LeaderHandler {
+	return &LeaderHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *LeaderHandler) Command() string {
+	return "leader"
+}
+
+// Validate returns whether the user input is valid.
+func (h *LeaderHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *LeaderHandler) Syntax() string {
+	return "leader [options]"
+}
+
+// Description returns a string that describes the command.
+func (h *LeaderHandler) Description() string {
+	return "Displays the leader of the cluster"
+}
+
+// Handles the "leader" command.
+func (h *LeaderHandler) Handle(i *Input) (string, error) {
+	opts, _, err := setupClusterOptions(h, h.controller.Stdout(), i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	leader, stats, err := h.controller.clusterLeader()
+	if err != nil {
+		return "", fmt.Errorf("leader: %s", err)
+	}
+
+	if opts.Output == "json" {
+		b, err := json.MarshalIndent(leader, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	}
+
+	return fmt.Sprintf(
+		"%s (%s) %s, leader for %s\n",
+		leader.Name,
+		leader.ID,
+		strings.Join(leader.ClientURLs, ","),
+		stats.LeaderInfo.Uptime,
+	), nil
+}
@@ -0,0 +1,284 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+const (
+	// Key listed by "lock -list" when no key is given.
+	DefaultLockDir = "/locks"
+
+	// Default TTL of a lock. The lock is refreshed while the command is running.
+	DefaultLockTTL = 30 * time.Second
+
+	// How often a held lock is checked for while waiting.
+	LockPollInterval = 500 * time.Millisecond
+)
+
+// Command line options for the lock command.
+type LockOptions struct {
+	PrintHelp bool
+	List      bool
+	TTL       time.Duration
+	Wait      time.Duration
+}
+
+// LockHandler handles the "lock" command.
+type LockHandler struct {
+	controller *Controller
+}
+
+// A lock held by the "lock" command.
+type heldLock struct {
+	client *etcd.Client
+	key    string
+	value  string
+	ttl    uint64
+	index  uint64
+	stderr io.Writer
+	stop   chan bool
+	done   chan bool
+}
+
+// NewLockHandler returns a new LockHandler instance.
+func NewLockHandler(controller *Controller) *LockHandler {
+	return &LockHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *LockHandler) Command() string {
+	return "lock"
+}
+
+// Validate returns whether the user input is valid.
+func (h *LockHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *LockHandler) Syntax() string {
+	return "lock [options] <path> -- <command>"
+}
+
+// Description returns a string that describes the command.
+func (h *LockHandler) Description() string {
+	return "Runs a command while holding a lock"
+}
+
+// Handles the "lock" command, holding a lock object with a TTL while the command runs.
+func (h *LockHandler) Handle(i *Input) (string, error) {
+	opts, args, command, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+	if opts.List {
+		return h.list(args)
+	}
+
+	ttl := uint64(opts.TTL / time.Second)
+	if ttl == 0 {
+		return "", errors.New("lock: the TTL must be at least one second")
+	}
+
+	key := h.controller.WorkingDir(args[0])
+	lock, err := h.acquire(key, ttl, opts.Wait)
+	if err != nil {
+		return "", err
+	}
+	go lock.keepAlive()
+
+	in := NewInput(command[0])
+	in.Args = command[1:]
+	status := h.controller.handleInput(in)
+
+	err = lock.release()
+	if err != nil {
+		return "", err
+	}
+	if status != StatusSuccess {
+		return "", &StatusError{Status: status}
+	}
+
+	return "", nil
+}
+
+// acquire creates the lock object, waiting up to wait for another holder to release it.
+func (h *LockHandler) acquire(key string, ttl uint64, wait time.Duration) (*heldLock, error) {
+	client := h.controller.Client()
+	value := lockHolder()
+	deadline := time.Now().Add(wait)
+	for {
+		resp, err := client.Create(key, value, ttl)
+		if err == nil {
+			lock := &heldLock{
+				client: client,
+				key:    key,
+				value:  value,
+				ttl:    ttl,
+				index:  resp.Node.ModifiedIndex,
+				stderr: h.controller.Stderr(),
+				stop:   make(chan bool),
+				done:   make(chan bool),
+			}
+			return lock, nil
+		}
+		if etcdErrorCode(err) != EcodeNodeExist {
+			return nil, err
+		}
+
+		if !time.Now().Before(deadline) {
+			holder := "another client"
+			resp, err := client.Get(key, false, false)
+			if err == nil {
+				holder = resp.Node.Value
+			}
+			return nil, fmt.Errorf("lock: %s is held by %s", key, holder)
+		}
+		time.Sleep(LockPollInterval)
+	}
+}
+
+// list returns the holder and remaining TTL of every lock below the given key.
+func (h *LockHandler) list(args []string) (string, error) {
+	dir := DefaultLockDir
+	if len(args) > 0 {
+		dir = h.controller.WorkingDir(args[0])
+	}
+
+	resp, err := h.controller.Client().Get(dir, true, true)
+	if err != nil {
+		return "", err
+	}
+
+	output := bytes.NewBufferString("")
+	h.writeLocks(output, resp.Node)
+	return output.String(), nil
+}
+
+// writeLocks writes each lock object below node to the output.
+func (h *LockHandler) writeLocks(output *bytes.Buffer, node *etcd.Node) {
+	if !node.Dir {
+		output.WriteString(fmt.Sprintf("%s %s (ttl %d)\n", node.Key, node.Value, node.TTL))
+		return
+	}
+	for _, n := range node.Nodes {
+		h.writeLocks(output, n)
+	}
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command. The command to run
+// follows "--", and is returned separately from the other args.
+func (h *LockHandler) setupOptions(args []string) (*LockOptions, []string, []string, error) {
+	opts := &LockOptions{}
+	flags := flag.NewFlagSet("lock_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.List, "list", false, "List the current lock holders")
+	flags.DurationVar(&opts.TTL, "ttl", DefaultLockTTL, "TTL of the lock")
+	flags.DurationVar(&opts.Wait, "wait", 0, "How long to wait for the lock to be released")
+
+	command := []string{}
+	for index, arg := range args {
+		if arg == "--" {
+			args, command = args[:index], args[index+1:]
+			break
+		}
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || (!opts.List && (len(args) != 1 || len(command) == 0)) {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, command, nil
+}
+
+// keepAlive refreshes the TTL of the lock until the lock is released.
+func (l *heldLock) keepAlive() {
+	defer close(l.done)
+	ticker := time.NewTicker(time.Duration(l.ttl) * time.Second / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			resp, err := l.client.CompareAndSwap(l.key, l.value, l.ttl, "", l.index)
+			if err != nil {
+				fmt.Fprintf(l.stderr, "lock: lost %s: %s\n", l.key, err)
+				return
+			}
+			l.index = resp.Node.ModifiedIndex
+		}
+	}
+}
+
+// release stops refreshing the lock and removes it.
+func (l *heldLock) release() error {
+	close(l.stop)
+	<-l.done
+
+	_, err := l.client.CompareAndDelete(l.key, "", l.index)
+	if err != nil {
+		return fmt.Errorf("lock: could not release %s: %s", l.key, err)
+	}
+
+	return nil
+}
+
+// lockHolder returns the value stored in a lock to identify the holder.
+func lockHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	name := "unknown"
+	usr, err := user.Current()
+	if err == nil {
+		name = usr.Username
+	}
+
+	return fmt.Sprintf("%s@%s:%d", name, host, os.Getpid())
+}
@@ -30,9 +30,11 @@ import (
 	"fmt"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 
 	"github.com/coreos/go-etcd/etcd"
+	"github.com/headzoo/etcdsh/config"
 	"github.com/headzoo/etcdsh/env"
 )
 
@@ -120,6 +122,9 @@ func (h *LsHandler) Handle(i *Input) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if opts.Sorted && isInOrderDir(resp.Node) {
+		sort.Sort(nodesByCreatedIndex(resp.Node.Nodes))
+	}
 
 	if opts.LongFormat {
 		return h.respToLongOutput(resp), nil
@@ -133,14 +138,14 @@ func (h *LsHandler) setupOptions(args []string) (*LsOptions, []string, error) {
 	flags := flag.NewFlagSet("ls_flags", flag.ContinueOnError)
 	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
 	flags.BoolVar(&opts.LongFormat, "l", false, "Use long list format")
-	flags.BoolVar(&opts.Sorted, "s", false, "Sort the results")
+	flags.BoolVar(&opts.Sorted, "s", false, "Sort the results, queues are sorted by created index")
 
 	err := flags.Parse(args)
 	if err != nil {
 		return nil, nil, err
 	}
 	if opts.PrintHelp {
-		printCommandHelp(h, flags)
+		printCommandHelp(h.controller.Stdout(), h, flags)
 		return nil, nil, nil
 	}
 
@@ -230,7 +235,6 @@ func (h *LsHandler) formatNodeLong(n *etcd.Node, w LsColumnWidths) string {
 		if n.Dir {
 			prefix = env.ColorPrefixCode(h.colors.Key)
 		} else {
-			fmt.Println(h.colors.Object)
 			prefix = env.ColorPrefixCode(h.colors.Object)
 		}
 		postfix = env.ColorPostfixCode()
@@ -253,19 +257,25 @@ func (h *LsHandler) formatNodeLong(n *etcd.Node, w LsColumnWidths) string {
 
 // setupColors sets the value of LsHandler.colors.
 func (h *LsHandler) setupColors() {
-	h.colors = LsOutputColors{}
-	h.use_colors = false
-
-	if h.controller.Config().Colors && runtime.GOOS == "linux" {
-		envColors := env.NewColors()
-		di, _ := envColors.GetLSDefault("di", DefaultColorKeys)
-		fi, _ := envColors.GetLSDefault("fi", DefaultColorObjects)
-		h.colors = LsOutputColors{
-			Key:    di,
-			Object: fi,
-		}
-		h.use_colors = true
+	h.colors, h.use_colors = outputColors(h.controller.Config())
+}
+
+// outputColors returns the colors used to display keys and objects, and whether colors
+// should be used at all. The colors are read from the LS_COLORS environment variable.
+func outputColors(conf *config.Config) (LsOutputColors, bool) {
+	if !conf.Colors || runtime.GOOS != "linux" {
+		return LsOutputColors{}, false
 	}
+
+	envColors := env.NewColors()
+	di, _ := envColors.GetLSDefault("di", DefaultColorKeys)
+	fi, _ := envColors.GetLSDefault("fi", DefaultColorObjects)
+	colors := LsOutputColors{
+		Key:    di,
+		Object: fi,
+	}
+
+	return colors, true
 }
 
 // columnWidths returns the widths for each column in the "ls" output.
@@ -0,0 +1,129 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command line options for the members, leader and stats commands.
+type ClusterOptions struct {
+	PrintHelp bool
+	Output    string
+}
+
+// MembersHandler handles the "members" command.
+type MembersHandler struct {
+	controller *Controller
+}
+
+// NewMembersHandler returns a new MembersHandler instance.
+func NewMembersHandler(controller *Controller) *MembersHandler {
+	return &MembersHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *MembersHandler) Command() string {
+	return "members"
+}
+
+// Validate returns whether the user input is valid.
+func (h *MembersHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *MembersHandler) Syntax() string {
+	return "members [options]"
+}
+
+// Description returns a string that describes the command.
+func (h *MembersHandler) Description() string {
+	return "Displays the members of the cluster"
+}
+
+// Handles the "members" command.
+func (h *MembersHandler) Handle(i *Input) (string, error) {
+	opts, _, err := setupClusterOptions(h, h.controller.Stdout(), i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	members := &clusterMembers{}
+	body, err := h.controller.clusterGet("/v2/members", members)
+	if err != nil {
+		return "", err
+	}
+	if opts.Output == "json" {
+		return indentJSON(body)
+	}
+
+	output := bytes.NewBufferString("")
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPEER URLS\tCLIENT URLS")
+	for _, m := range members.Members {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			m.ID,
+			m.Name,
+			strings.Join(m.PeerURLs, ","),
+			strings.Join(m.ClientURLs, ","),
+		)
+	}
+	w.Flush()
+
+	return output.String(), nil
+}
+
+// setupClusterOptions builds a FlagSet and parses the args passed to the cluster commands.
+// Help is written to w.
+func setupClusterOptions(h Handler, w io.Writer, args []string) (*ClusterOptions, []string, error) {
+	opts := &ClusterOptions{}
+	flags := flag.NewFlagSet(h.Command()+"_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Output, "o", "", "Output format, use json for the raw response")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(w, h, flags)
+		return nil, nil, nil
+	}
+	if opts.Output != "" && opts.Output != "json" {
+		return nil, nil, fmt.Errorf("%s: unknown output format '%s'", h.Command(), opts.Output)
+	}
+
+	return opts, flags.Args(), nil
+}
@@ -0,0 +1,165 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the mkdir command.
+type MkdirOptions struct {
+	PrintHelp bool
+	Parents   bool
+	TTL       uint64
+}
+
+// MkdirHandler handles the "mkdir" command.
+type MkdirHandler struct {
+	controller *Controller
+}
+
+// NewMkdirHandler returns a new MkdirHandler instance.
+func NewMkdirHandler(controller *Controller) *MkdirHandler {
+	return &MkdirHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *MkdirHandler) Command() string {
+	return "mkdir"
+}
+
+// Validate returns whether the user input is valid.
+func (h *MkdirHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *MkdirHandler) Syntax() string {
+	return "mkdir [options] <path>..."
+}
+
+// Description returns a string that describes the command.
+func (h *MkdirHandler) Description() string {
+	return "Creates keys"
+}
+
+// Handles the "mkdir" command.
+func (h *MkdirHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	failed := []string{}
+	for _, arg := range args {
+		dir := h.controller.WorkingDir(arg)
+		if opts.Parents {
+			err = h.mkdirParents(dir, opts.TTL)
+		} else {
+			_, err = h.controller.Client().CreateDir(dir, opts.TTL)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("mkdir: cannot create key '%s': %s", arg, err))
+		}
+	}
+	h.controller.RefreshWorkingDirKeys()
+
+	if len(failed) > 0 {
+		return "", errors.New(strings.Join(failed, "\n"))
+	}
+	return "", nil
+}
+
+// mkdirParents creates dir along with any missing parent keys. Keys which already exist are
+// left alone, except the TTL of dir is updated when ttl is not 0.
+func (h *MkdirHandler) mkdirParents(dir string, ttl uint64) error {
+	parts := strings.Split(strings.Trim(dir, "/"), "/")
+	current := "/"
+	for index, part := range parts {
+		current = path.Join(current, part)
+		dirTTL := uint64(0)
+		if index == len(parts)-1 {
+			dirTTL = ttl
+		}
+		err := ensureDir(h.controller.Client(), current, dirTTL)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *MkdirHandler) setupOptions(args []string) (*MkdirOptions, []string, error) {
+	opts := &MkdirOptions{}
+	flags := flag.NewFlagSet("mkdir_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Parents, "p", false, "Create parent keys as needed, no error if existing")
+	flags.Uint64Var(&opts.TTL, "t", 0, "Sets the TTL")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) == 0 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
+
+// ensureDir creates the key dir when it does not exist. An error is returned when an object
+// already exists with the same name.
+func ensureDir(client *etcd.Client, dir string, ttl uint64) error {
+	_, err := client.CreateDir(dir, ttl)
+	if err == nil || etcdErrorCode(err) != EcodeNodeExist {
+		return err
+	}
+
+	resp, err := client.Get(dir, false, false)
+	if err != nil {
+		return err
+	}
+	if !resp.Node.Dir {
+		return fmt.Errorf("%s is an object, not a key", dir)
+	}
+	if ttl > 0 {
+		_, err = client.UpdateDir(dir, ttl)
+	}
+
+	return err
+}
@@ -0,0 +1,125 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// MvHandler handles the "mv" command.
+type MvHandler struct {
+	controller *Controller
+}
+
+// NewMvHandler returns a new MvHandler instance.
+func NewMvHandler(controller *Controller) *MvHandler {
+	return &MvHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *MvHandler) Command() string {
+	return "mv"
+}
+
+// Validate returns whether the user input is valid.
+func (h *MvHandler) Validate(i *Input) bool {
+	return len(i.Args) == 2
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *MvHandler) Syntax() string {
+	return "mv <source> <dest>"
+}
+
+// Description returns a string that describes the command.
+func (h *MvHandler) Description() string {
+	return "Moves or renames an object or key"
+}
+
+// Handles the "mv" command by copying the source to the destination and then removing it.
+func (h *MvHandler) Handle(i *Input) (string, error) {
+	client := h.controller.Client()
+	src := h.controller.WorkingDir(i.Args[0])
+	resp, err := client.Get(src, true, true)
+	if err != nil {
+		return "", err
+	}
+
+	dst, err := copyDestination(client, resp.Node, h.controller.WorkingDir(i.Args[1]))
+	if err != nil {
+		return "", err
+	}
+	err = copyNode(client, resp.Node, dst)
+	if err != nil {
+		h.controller.RefreshWorkingDirKeys()
+		return "", fmt.Errorf("mv: copy failed, '%s' was not removed: %s", src, err)
+	}
+
+	failed := []string{}
+	removeMovedNode(client, resp.Node, &failed)
+	h.controller.RefreshWorkingDirKeys()
+
+	if len(failed) > 0 {
+		return "", errors.New(strings.Join(failed, "\n"))
+	}
+	return "", nil
+}
+
+// removeMovedNode removes node and the nodes below it, deepest first. Failures are appended
+// to failed, and keys which still have children after a failure are left in place. Returns
+// whether node was removed.
+func removeMovedNode(client *etcd.Client, node *etcd.Node, failed *[]string) bool {
+	var err error
+	if node.Dir {
+		removed := true
+		for _, n := range node.Nodes {
+			if !removeMovedNode(client, n, failed) {
+				removed = false
+			}
+		}
+		if !removed {
+			return false
+		}
+		_, err = client.DeleteDir(node.Key)
+	} else {
+		_, err = client.CompareAndDelete(node.Key, "", node.ModifiedIndex)
+	}
+
+	if err != nil {
+		if etcdErrorCode(err) == EcodeTestFailed {
+			err = errors.New("modified by another client during the move")
+		}
+		*failed = append(*failed, fmt.Sprintf("mv: cannot remove '%s': %s", node.Key, err))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+)
+
+// PeekHandler handles the "peek" command.
+type PeekHandler struct {
+	controller *Controller
+}
+
+// NewPeekHandler returns a new PeekHandler instance.
+func NewPeekHandler(controller *Controller) *PeekHandler {
+	return &PeekHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *PeekHandler) Command() string {
+	return "peek"
+}
+
+// Validate returns whether the user input is valid.
+func (h *PeekHandler) Validate(i *Input) bool {
+	return len(i.Args) == 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *PeekHandler) Syntax() string {
+	return "peek <queue>"
+}
+
+// Description returns a string that describes the command.
+func (h *PeekHandler) Description() string {
+	return "Displays the oldest value in a queue without removing it"
+}
+
+// Handles the "peek" command.
+func (h *PeekHandler) Handle(i *Input) (string, error) {
+	dir := h.controller.WorkingDir(i.Args[0])
+	items, err := queueItems(h.controller.Client(), dir)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("peek: %s is empty", dir)
+	}
+
+	return fmt.Sprintf("%s\n", items[0].Value), nil
+}
@@ -0,0 +1,89 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// Shell is the shell used to run the commands which etcdsh output is piped into.
+const Shell = "/bin/sh"
+
+// runPipeline runs the etcdsh command, and streams its output into the stdin of pipeline,
+// which is run by the shell. Returns the status of pipeline when it fails, and otherwise the
+// status of the etcdsh command.
+func (c *Controller) runPipeline(command, pipeline string) int {
+	if strings.TrimSpace(command) == "" || strings.TrimSpace(pipeline) == "" {
+		fmt.Fprintln(c.stderr, "syntax error near unexpected token '|'")
+		return StatusFailure
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+	cmd := exec.Command(Shell, "-c", pipeline)
+	cmd.Stdin = reader
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+	err = cmd.Start()
+	reader.Close()
+	if err != nil {
+		writer.Close()
+		fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+
+	stdout := c.stdout
+	c.stdout = writer
+	status := c.runInput(command)
+	c.stdout = stdout
+	writer.Close()
+
+	err = cmd.Wait()
+	if err != nil {
+		return exitStatus(err, c.stderr)
+	}
+
+	return status
+}
+
+// exitStatus returns the exit status of a command which returned err from exec.Cmd.Wait().
+// Errors other than a non-zero exit status are written to stderr.
+func exitStatus(err error, stderr *os.File) int {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	fmt.Fprintln(stderr, err)
+
+	return StatusFailure
+}
@@ -0,0 +1,89 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+)
+
+// PopHandler handles the "pop" command.
+type PopHandler struct {
+	controller *Controller
+}
+
+// NewPopHandler returns a new PopHandler instance.
+func NewPopHandler(controller *Controller) *PopHandler {
+	return &PopHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *PopHandler) Command() string {
+	return "pop"
+}
+
+// Validate returns whether the user input is valid.
+func (h *PopHandler) Validate(i *Input) bool {
+	return len(i.Args) == 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *PopHandler) Syntax() string {
+	return "pop <queue>"
+}
+
+// Description returns a string that describes the command.
+func (h *PopHandler) Description() string {
+	return "Removes and displays the oldest value in a queue"
+}
+
+// Handles the "pop" command, trying the next oldest item when another client pops it first.
+func (h *PopHandler) Handle(i *Input) (string, error) {
+	client := h.controller.Client()
+	dir := h.controller.WorkingDir(i.Args[0])
+	for attempt := 0; attempt < QueuePopRetries; attempt++ {
+		items, err := queueItems(client, dir)
+		if err != nil {
+			return "", err
+		}
+		if len(items) == 0 {
+			return "", fmt.Errorf("pop: %s is empty", dir)
+		}
+
+		item := items[0]
+		_, err = client.CompareAndDelete(item.Key, "", item.ModifiedIndex)
+		if err == nil {
+			h.controller.RefreshWorkingDirKeys()
+			return fmt.Sprintf("%s\n", item.Value), nil
+		}
+		code := etcdErrorCode(err)
+		if code != EcodeTestFailed && code != EcodeKeyNotFound {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("pop: gave up after %d attempts, %s is busy", QueuePopRetries, dir)
+}
@@ -0,0 +1,83 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+)
+
+// PopdHandler handles the "popd" command.
+type PopdHandler struct {
+	controller *Controller
+}
+
+// NewPopdHandler returns a new PopdHandler instance.
+func NewPopdHandler(controller *Controller) *PopdHandler {
+	return &PopdHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *PopdHandler) Command() string {
+	return "popd"
+}
+
+// Validate returns whether the user input is valid.
+func (h *PopdHandler) Validate(i *Input) bool {
+	return len(i.Args) == 0 || (len(i.Args) == 1 && isStackIndex(i.Args[0]))
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *PopdHandler) Syntax() string {
+	return "popd [+N|-N]"
+}
+
+// Description returns a string that describes the command.
+func (h *PopdHandler) Description() string {
+	return "Removes a directory from the directory stack"
+}
+
+// Handles the "popd" command.
+//
+// Like bash, without arguments the top directory is removed and the working directory changes
+// to the next one, and +N or -N removes the Nth directory counting from the left or right.
+func (h *PopdHandler) Handle(i *Input) (string, error) {
+	n := 0
+	if len(i.Args) > 0 {
+		var err error
+		n, err = stackIndex(i.Args[0], len(h.controller.WorkingDirStack()))
+		if err != nil {
+			return "", fmt.Errorf("popd: %s", err)
+		}
+	}
+
+	_, err := h.controller.PopWorkingDir(n)
+	if err != nil {
+		return "", fmt.Errorf("popd: %s", err)
+	}
+
+	return formatWorkingDirStack(h.controller.WorkingDirStack()), nil
+}
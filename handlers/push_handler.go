@@ -0,0 +1,106 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Command line options for the push command.
+type PushOptions struct {
+	PrintHelp bool
+	TTL       uint64
+}
+
+// PushHandler handles the "push" command.
+type PushHandler struct {
+	controller *Controller
+}
+
+// NewPushHandler returns a new PushHandler instance.
+func NewPushHandler(controller *Controller) *PushHandler {
+	return &PushHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *PushHandler) Command() string {
+	return "push"
+}
+
+// Validate returns whether the user input is valid.
+func (h *PushHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *PushHandler) Syntax() string {
+	return "push [options] <queue> <value>"
+}
+
+// Description returns a string that describes the command.
+func (h *PushHandler) Description() string {
+	return "Adds a value to the end of a queue"
+}
+
+// Handles the "push" command.
+func (h *PushHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	dir := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().CreateInOrder(dir, args[1], opts.TTL)
+	if err != nil {
+		return "", err
+	}
+	h.controller.RefreshWorkingDirKeys()
+
+	return fmt.Sprintf("%s\n", resp.Node.Key), nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *PushHandler) setupOptions(args []string) (*PushOptions, []string, error) {
+	opts := &PushOptions{}
+	flags := flag.NewFlagSet("push_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.Uint64Var(&opts.TTL, "t", 0, "Sets the TTL")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) != 2 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
@@ -0,0 +1,125 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PushdHandler handles the "pushd" command.
+type PushdHandler struct {
+	controller *Controller
+}
+
+// NewPushdHandler returns a new PushdHandler instance.
+func NewPushdHandler(controller *Controller) *PushdHandler {
+	return &PushdHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *PushdHandler) Command() string {
+	return "pushd"
+}
+
+// Validate returns whether the user input is valid.
+func (h *PushdHandler) Validate(i *Input) bool {
+	return len(i.Args) < 2
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *PushdHandler) Syntax() string {
+	return "pushd [directory|+N|-N]"
+}
+
+// Description returns a string that describes the command.
+func (h *PushdHandler) Description() string {
+	return "Pushes a directory onto the directory stack and changes to it"
+}
+
+// Handles the "pushd" command.
+//
+// Like bash, without arguments the top two directories are exchanged, and +N or -N rotates
+// the stack so the Nth directory, counting from the left or right, becomes the working
+// directory.
+func (h *PushdHandler) Handle(i *Input) (string, error) {
+	var err error
+	if len(i.Args) == 0 {
+		if len(h.controller.WorkingDirStack()) < 2 {
+			return "", fmt.Errorf("pushd: no other directory")
+		}
+		_, err = h.controller.PushWorkingDir(h.controller.WorkingDirStack()[1])
+		if err == nil {
+			_, err = h.controller.PopWorkingDir(2)
+		}
+	} else if isStackIndex(i.Args[0]) {
+		var n int
+		n, err = stackIndex(i.Args[0], len(h.controller.WorkingDirStack()))
+		if err == nil {
+			_, err = h.controller.RotateWorkingDirStack(n)
+		}
+	} else {
+		_, err = h.controller.PushWorkingDir(i.Args[0])
+	}
+	if err != nil {
+		return "", fmt.Errorf("pushd: %s", err)
+	}
+
+	return formatWorkingDirStack(h.controller.WorkingDirStack()), nil
+}
+
+// isStackIndex returns whether arg is a directory stack index such as +1 or -2.
+func isStackIndex(arg string) bool {
+	if len(arg) < 2 || (arg[0] != '+' && arg[0] != '-') {
+		return false
+	}
+	_, err := strconv.Atoi(arg[1:])
+
+	return err == nil
+}
+
+// stackIndex converts a directory stack index such as +1 or -2 into a position in a stack of
+// the given size. Indexes starting with "+" count from the left, and indexes starting with
+// "-" count from the right, both starting at zero.
+func stackIndex(arg string, size int) (int, error) {
+	n, err := strconv.Atoi(arg[1:])
+	if err != nil || n < 0 || n >= size {
+		return 0, fmt.Errorf("%s: directory stack index out of range", arg)
+	}
+	if arg[0] == '-' {
+		n = size - 1 - n
+	}
+
+	return n, nil
+}
+
+// formatWorkingDirStack formats the directory stack for display, with the entries separated by
+// spaces.
+func formatWorkingDirStack(stack []string) string {
+	return strings.Join(stack, " ") + "\n"
+}
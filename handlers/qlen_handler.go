@@ -0,0 +1,72 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+)
+
+// QlenHandler handles the "qlen" command.
+type QlenHandler struct {
+	controller *Controller
+}
+
+// NewQlenHandler returns a new QlenHandler instance.
+func NewQlenHandler(controller *Controller) *QlenHandler {
+	return &QlenHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *QlenHandler) Command() string {
+	return "qlen"
+}
+
+// Validate returns whether the user input is valid.
+func (h *QlenHandler) Validate(i *Input) bool {
+	return len(i.Args) == 1
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *QlenHandler) Syntax() string {
+	return "qlen <queue>"
+}
+
+// Description returns a string that describes the command.
+func (h *QlenHandler) Description() string {
+	return "Displays the number of values in a queue"
+}
+
+// Handles the "qlen" command.
+func (h *QlenHandler) Handle(i *Input) (string, error) {
+	dir := h.controller.WorkingDir(i.Args[0])
+	items, err := queueItems(h.controller.Client(), dir)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d\n", len(items)), nil
+}
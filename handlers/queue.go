@@ -0,0 +1,83 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Number of times "pop" tries to remove the oldest item before giving up, when other clients
+// keep removing the item first.
+const QueuePopRetries = 10
+
+// nodesByCreatedIndex sorts nodes by the index they were created at.
+type nodesByCreatedIndex etcd.Nodes
+
+func (ns nodesByCreatedIndex) Len() int           { return len(ns) }
+func (ns nodesByCreatedIndex) Less(i, j int) bool { return ns[i].CreatedIndex < ns[j].CreatedIndex }
+func (ns nodesByCreatedIndex) Swap(i, j int)      { ns[i], ns[j] = ns[j], ns[i] }
+
+// queueItems returns the objects in the queue dir, oldest first.
+func queueItems(client *etcd.Client, dir string) (etcd.Nodes, error) {
+	resp, err := client.Get(dir, true, false)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Node.Dir {
+		return nil, fmt.Errorf("%s is an object, not a queue", dir)
+	}
+
+	items := etcd.Nodes{}
+	for _, n := range resp.Node.Nodes {
+		if !n.Dir {
+			items = append(items, n)
+		}
+	}
+	sort.Sort(items)
+
+	return items, nil
+}
+
+// isInOrderDir returns whether every child of node was created by a POST to the directory,
+// which gives each child a numeric name.
+func isInOrderDir(node *etcd.Node) bool {
+	if len(node.Nodes) == 0 {
+		return false
+	}
+	for _, n := range node.Nodes {
+		name := path.Base(n.Key)
+		for _, ch := range name {
+			if ch < '0' || ch > '9' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
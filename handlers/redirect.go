@@ -0,0 +1,171 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/flynn/go-shlex"
+)
+
+// redirect is a redirection parsed from a command.
+type redirect struct {
+	// Op is one of ">", ">>", "2>", "2>>" or "<".
+	Op string
+
+	// File is the file being redirected to or from.
+	File string
+}
+
+// parseRedirects removes the redirections from command, and returns the command without them.
+// Quoted and escaped redirection operators are left in the command.
+func parseRedirects(command string) (string, []redirect, error) {
+	runes := []rune(command)
+	stripped := []rune{}
+	redirects := []redirect{}
+	var quote rune
+	escaped := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '>' || r == '<':
+			op := string(r)
+			if r == '>' && i+1 < len(runes) && runes[i+1] == '>' {
+				op = ">>"
+				i++
+			}
+			n := len(stripped)
+			if r == '>' && n > 0 && stripped[n-1] == '2' && (n == 1 || unicode.IsSpace(stripped[n-2])) {
+				op = "2" + op
+				stripped = stripped[:n-1]
+			}
+
+			word, end := redirectTarget(runes, i+1)
+			file, err := shlex.Split(word)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(file) != 1 {
+				return "", nil, fmt.Errorf("syntax error near unexpected token '%s'", op)
+			}
+			redirects = append(redirects, redirect{Op: op, File: file[0]})
+			i = end - 1
+			continue
+		}
+		stripped = append(stripped, r)
+	}
+
+	return string(stripped), redirects, nil
+}
+
+// redirectTarget returns the word following a redirection operator, starting at runes[start],
+// and the index of the first rune after the word.
+func redirectTarget(runes []rune, start int) (string, int) {
+	for start < len(runes) && unicode.IsSpace(runes[start]) {
+		start++
+	}
+
+	end := start
+	var quote rune
+	escaped := false
+	for ; end < len(runes); end++ {
+		r := runes[end]
+		if escaped {
+			escaped = false
+		} else if r == '\\' && quote != '\'' {
+			escaped = true
+		} else if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+		} else if r == '\'' || r == '"' {
+			quote = r
+		} else if unicode.IsSpace(r) || strings.ContainsRune("<>", r) {
+			break
+		}
+	}
+
+	return string(runes[start:end]), end
+}
+
+// applyRedirects opens the files redirected to or from, and points the controller stdout and
+// stderr at them. The contents of files redirected from are appended to the input args, so
+// commands which accept a value can read it from a file. The returned function restores
+// stdout and stderr, and closes the files.
+func (c *Controller) applyRedirects(i *Input, redirects []redirect) (func(), error) {
+	stdout, stderr := c.stdout, c.stderr
+	files := []*os.File{}
+	restore := func() {
+		c.stdout, c.stderr = stdout, stderr
+		for _, file := range files {
+			file.Close()
+		}
+	}
+
+	for _, r := range redirects {
+		if r.Op == "<" {
+			data, err := ioutil.ReadFile(r.File)
+			if err != nil {
+				restore()
+				return nil, err
+			}
+			i.Args = append(i.Args, string(data))
+			continue
+		}
+
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if strings.HasSuffix(r.Op, ">>") {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		file, err := os.OpenFile(r.File, flags, 0644)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+		files = append(files, file)
+		if strings.HasPrefix(r.Op, "2") {
+			c.stderr = file
+		} else {
+			c.stdout = file
+		}
+	}
+
+	return restore, nil
+}
@@ -0,0 +1,130 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Command line options for the rm command.
+type RmOptions struct {
+	PrintHelp bool
+	Recursive bool
+	Dir       bool
+}
+
+// RmHandler handles the "rm" command.
+type RmHandler struct {
+	controller *Controller
+}
+
+// NewRmHandler returns a new RmHandler instance.
+func NewRmHandler(controller *Controller) *RmHandler {
+	return &RmHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *RmHandler) Command() string {
+	return "rm"
+}
+
+// Validate returns whether the user input is valid.
+func (h *RmHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *RmHandler) Syntax() string {
+	return "rm [options] <path>..."
+}
+
+// Description returns a string that describes the command.
+func (h *RmHandler) Description() string {
+	return "Removes objects, or keys when used with -d or -r"
+}
+
+// Handles the "rm" command.
+func (h *RmHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	failed := []string{}
+	for _, arg := range args {
+		err = h.remove(h.controller.WorkingDir(arg), opts)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("rm: cannot remove '%s': %s", arg, err))
+		}
+	}
+	h.controller.RefreshWorkingDirKeys()
+
+	if len(failed) > 0 {
+		return "", errors.New(strings.Join(failed, "\n"))
+	}
+	return "", nil
+}
+
+// remove deletes a single key using the mode selected by the options.
+func (h *RmHandler) remove(key string, opts *RmOptions) error {
+	client := h.controller.Client()
+	var err error
+	if opts.Recursive {
+		_, err = client.Delete(key, true)
+	} else if opts.Dir {
+		_, err = client.DeleteDir(key)
+	} else {
+		_, err = client.Delete(key, false)
+	}
+
+	return err
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *RmHandler) setupOptions(args []string) (*RmOptions, []string, error) {
+	opts := &RmOptions{}
+	flags := flag.NewFlagSet("rm_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Recursive, "r", false, "Remove keys and their contents recursively")
+	flags.BoolVar(&opts.Dir, "d", false, "Remove empty keys")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) == 0 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
@@ -0,0 +1,184 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flynn/go-shlex"
+)
+
+// Run executes the commands read from r without displaying a prompt, and returns the status
+// of the first command which failed. Commands are separated by new lines or ";", lines
+// ending with "\" are continued on the next line, and lines starting with "#" are comments,
+// which allows scripts to start with a "#!/usr/bin/env etcdsh" line. When exitOnError is
+// true no more commands are run after a command fails.
+func (c *Controller) Run(r io.Reader, exitOnError bool) int {
+	c.nonInteractive = true
+	status := StatusSuccess
+	scanner := bufio.NewScanner(r)
+	buffer := bytes.NewBufferString("")
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if buffer.Len() == 0 && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			buffer.WriteString(strings.TrimSuffix(line, "\\") + "\n")
+			continue
+		}
+		if buffer.Len() > 0 {
+			buffer.WriteString(line)
+			line = buffer.String()
+			buffer.Reset()
+		}
+
+		if isExitCommand(line) {
+			return status
+		}
+		s := c.runLine(line, exitOnError)
+		if s != StatusSuccess {
+			if exitOnError {
+				return s
+			}
+			if status == StatusSuccess {
+				status = s
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		if status == StatusSuccess {
+			status = StatusFailure
+		}
+	}
+
+	return status
+}
+
+// runLine runs each of the commands in line, which are separated by ";", and returns the
+// status of the first command which failed. When exitOnError is true no more commands are
+// run after a command fails.
+func (c *Controller) runLine(line string, exitOnError bool) int {
+	status := StatusSuccess
+	for _, command := range splitUnquoted(line, ';') {
+		s := c.runCommand(command)
+		if s != StatusSuccess {
+			if exitOnError {
+				return s
+			}
+			if status == StatusSuccess {
+				status = s
+			}
+		}
+	}
+
+	return status
+}
+
+// runCommand runs a single command, which may be piped into shell commands, and returns its
+// status.
+func (c *Controller) runCommand(command string) int {
+	commands := splitUnquoted(command, '|')
+	if len(commands) > 1 {
+		return c.runPipeline(commands[0], strings.Join(commands[1:], "|"))
+	}
+
+	return c.runInput(command)
+}
+
+// runInput parses and runs a single etcdsh command, including its redirections, and returns
+// its status. Blank commands succeed without doing anything.
+func (c *Controller) runInput(command string) int {
+	command, redirects, err := parseRedirects(command)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+	parts, err := shlex.Split(command)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+	if len(parts) == 0 {
+		return StatusSuccess
+	}
+
+	in := NewInput(parts[0])
+	if len(parts) > 1 {
+		in.Args = parts[1:]
+	}
+	restore, err := c.applyRedirects(in, redirects)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return StatusFailure
+	}
+	defer restore()
+
+	return c.handleInput(in)
+}
+
+// isExitCommand returns whether line is one of the commands which quits the shell.
+func isExitCommand(line string) bool {
+	line = strings.ToLower(line)
+	return line == "q" || line == "exit"
+}
+
+// splitUnquoted splits line at each sep which is not quoted or escaped with a backslash. The
+// quotes and backslashes are left in the returned strings.
+func splitUnquoted(line string, sep rune) []string {
+	parts := []string{}
+	part := []rune{}
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == sep:
+			parts = append(parts, string(part))
+			part = []rune{}
+			continue
+		}
+		part = append(part, r)
+	}
+
+	return append(parts, string(part))
+}
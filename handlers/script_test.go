@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/headzoo/etcdsh/config"
+)
+
+// testHandler records and outputs the arguments of the commands it handles, and fails when the first argument is "fail".
+type testHandler struct {
+	calls []string
+}
+
+func (h *testHandler) Command() string        { return "test" }
+func (h *testHandler) Validate(i *Input) bool { return true }
+func (h *testHandler) Syntax() string         { return "test [fail]" }
+func (h *testHandler) Description() string    { return "Records the commands it handles" }
+func (h *testHandler) Handle(i *Input) (string, error) {
+	output := strings.Join(i.Args, " ")
+	h.calls = append(h.calls, output)
+	if len(i.Args) > 0 && i.Args[0] == "fail" {
+		return "", &StatusError{Status: 3}
+	}
+	return output + "\n", nil
+}
+
+func testController(t *testing.T) (*Controller, *testHandler) {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &testHandler{}
+	c := NewController(config.New(), nil, null, null, os.Stdin)
+	c.Add(handler)
+
+	return c, handler
+}
+
+func TestRunUsageError(t *testing.T) {
+	c, handler := testController(t)
+	c.Add(NewSetHandler(c))
+	status := c.Run(strings.NewReader("set -h; set /only-key; test one"), true)
+	if status != StatusFailure || len(handler.calls) != 0 {
+		t.Errorf("Run() = %d and ran %q, want 1 and nothing.", status, handler.calls)
+	}
+}
+
+func TestRunPrompt(t *testing.T) {
+	c, _ := testController(t)
+	c.Run(strings.NewReader(""), false)
+	_, err := c.Prompt("continue? ")
+	if err != ErrNotInteractive {
+		t.Errorf("Prompt() error = %v, want %v.", err, ErrNotInteractive)
+	}
+}
+
+func TestSplitUnquoted(t *testing.T) {
+	tests := map[string][]string{
+		"ls; get /a":        {"ls", " get /a"},
+		"set /a 'x;y'":      {"set /a 'x;y'"},
+		`set /a "x;y"; ls`:  {`set /a "x;y"`, " ls"},
+		`set /a x\;y`:       {`set /a x\;y`},
+		"ls;":               {"ls", ""},
+		`set /a "it's"; ls`: {`set /a "it's"`, " ls"},
+	}
+	for in, expected := range tests {
+		actual := splitUnquoted(in, ';')
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("splitUnquoted(%q) = %q, want %q.", in, actual, expected)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	script := "#!/usr/bin/env etcdsh\n\ntest one; test fail\n# comment\ntest \\\ntwo\n"
+	c, handler := testController(t)
+	status := c.Run(strings.NewReader(script), false)
+	if status != 3 {
+		t.Errorf("Run() = %d, want 3.", status)
+	}
+	expected := []string{"one", "fail", "two"}
+	if !reflect.DeepEqual(handler.calls, expected) {
+		t.Errorf("Run() ran %q, want %q.", handler.calls, expected)
+	}
+
+	c, handler = testController(t)
+	status = c.Run(strings.NewReader(script), true)
+	if status != 3 {
+		t.Errorf("Run() with exitOnError = %d, want 3.", status)
+	}
+	expected = []string{"one", "fail"}
+	if !reflect.DeepEqual(handler.calls, expected) {
+		t.Errorf("Run() with exitOnError ran %q, want %q.", handler.calls, expected)
+	}
+
+	c, handler = testController(t)
+	status = c.Run(strings.NewReader("test one\nexit\ntest two\n"), false)
+	if status != StatusSuccess || len(handler.calls) != 1 {
+		t.Errorf("Run() = %d and ran %q, want 0 and [one].", status, handler.calls)
+	}
+}
+
+func TestRunPipeline(t *testing.T) {
+	file, err := ioutil.TempFile("", "etcdsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	c, _ := testController(t)
+	c.stdout = file
+	tests := []struct {
+		line   string
+		status int
+	}{
+		{"test hello world | tr a-z A-Z | cut -d ' ' -f 2", StatusSuccess},
+		{"test one | exit 4", 4},
+		{"test fail | cat", 3},
+		{"| cat", StatusFailure},
+	}
+	for _, test := range tests {
+		status := c.runLine(test.line, false)
+		if status != test.status {
+			t.Errorf("runLine(%q) = %d, want %d.", test.line, status, test.status)
+		}
+	}
+
+	output, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "WORLD\n" {
+		t.Errorf("runLine() piped output = %q, want WORLD.", output)
+	}
+}
+
+func TestParseRedirects(t *testing.T) {
+	tests := []struct {
+		in        string
+		command   string
+		redirects []redirect
+	}{
+		{"ls /apps", "ls /apps", []redirect{}},
+		{"ls /apps > out", "ls /apps ", []redirect{{">", "out"}}},
+		{"ls /apps>>out 2> err", "ls /apps ", []redirect{{">>", "out"}, {"2>", "err"}}},
+		{"set /certs/ca < ca.pem", "set /certs/ca ", []redirect{{"<", "ca.pem"}}},
+		{"set /a v2>out", "set /a v2", []redirect{{">", "out"}}},
+		{"set /a '>' \">\" \\> b", "set /a '>' \">\" \\> b", []redirect{}},
+	}
+	for _, test := range tests {
+		command, redirects, err := parseRedirects(test.in)
+		if err != nil {
+			t.Errorf("parseRedirects(%q) returned error %s.", test.in, err)
+		} else if command != test.command || !reflect.DeepEqual(redirects, test.redirects) {
+			t.Errorf("parseRedirects(%q) = %q %v, want %q %v.", test.in, command, redirects, test.command, test.redirects)
+		}
+	}
+
+	_, _, err := parseRedirects("ls >")
+	if err == nil {
+		t.Error("parseRedirects(\"ls >\") did not return an error.")
+	}
+}
+
+func TestRunRedirects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "in"), []byte("from file"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := testController(t)
+	c.Add(NewSetHandler(c))
+	stdout := c.stdout
+	out, in, errs := filepath.Join(dir, "out"), filepath.Join(dir, "in"), filepath.Join(dir, "err")
+	lines := []string{
+		"set -h > " + filepath.Join(dir, "help"),
+		"test one > " + out,
+		"test two >> " + out,
+		"test value < " + in + " >> " + out,
+		"missing 2> " + errs,
+	}
+	for _, line := range lines {
+		c.runLine(line, false)
+	}
+	if c.stdout != stdout {
+		t.Error("runLine() did not restore stdout.")
+	}
+	help, err := ioutil.ReadFile(filepath.Join(dir, "help"))
+	if err != nil || !strings.HasPrefix(string(help), "SYNTAX") {
+		t.Errorf("runLine() wrote %q as the set help, want SYNTAX first.", help)
+	}
+
+	expected := map[string]string{
+		"out": "one\ntwo\nvalue from file\n",
+		"err": "The command missing does not exist.\n",
+	}
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(data) != content {
+			t.Errorf("runLine() wrote %q to %s, want %q.", data, name, content)
+		}
+	}
+}
@@ -27,12 +27,17 @@ package handlers
 import (
 	"flag"
 	"fmt"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
 )
 
 // Command line options for the ls command.
 type SetOptions struct {
-	PrintHelp bool
-	TTL       uint64
+	PrintHelp   bool
+	TTL         uint64
+	NoClobber   bool
+	Interactive bool
 }
 
 // SetHandler handles the "ls" command.
@@ -74,20 +79,78 @@ func (h *SetHandler) Handle(i *Input) (string, error) {
 		return "", err
 	}
 
-	resp, err := h.controller.Client().Set(args[0], args[1], opts.TTL)
+	client := h.controller.Client()
+	key := h.controller.WorkingDir(args[0])
+	var resp *etcd.Response
+	if opts.Interactive && !opts.NoClobber {
+		var ok bool
+		var index uint64
+		ok, index, err = h.confirmOverwrite(key)
+		if err != nil || !ok {
+			return "", err
+		}
+
+		// The object is only written when it has not changed since the user confirmed.
+		if index == 0 {
+			resp, err = client.Create(key, args[1], opts.TTL)
+		} else {
+			resp, err = client.CompareAndSwap(key, args[1], opts.TTL, "", index)
+		}
+		code := etcdErrorCode(err)
+		if code == EcodeNodeExist || code == EcodeTestFailed {
+			err = fmt.Errorf("set: %s was modified by another client, not overwritten", key)
+		}
+	} else if opts.NoClobber {
+		resp, err = client.Create(key, args[1], opts.TTL)
+		if etcdErrorCode(err) == EcodeNodeExist {
+			err = fmt.Errorf("set: %s already exists, not overwritten", key)
+		}
+	} else {
+		resp, err = client.Set(key, args[1], opts.TTL)
+	}
 	if err != nil {
 		return "", err
 	}
+	h.controller.RefreshWorkingDirKeys()
 
 	return fmt.Sprintf("%s\n", resp.Node.Value), nil
 }
 
+// confirmOverwrite asks the user whether an existing object should be overwritten, showing
+// the current value. Returns whether to overwrite, and the modified index of the value shown,
+// which is 0 when the object does not exist and the user was not asked.
+func (h *SetHandler) confirmOverwrite(key string) (bool, uint64, error) {
+	resp, err := h.controller.Client().Get(key, false, false)
+	if err != nil {
+		if etcdErrorCode(err) == EcodeKeyNotFound {
+			return true, 0, nil
+		}
+		return false, 0, err
+	}
+	if resp.Node.Dir {
+		return false, 0, fmt.Errorf("set: %s is a key, not an object", key)
+	}
+
+	answer, err := h.controller.Prompt(fmt.Sprintf("overwrite %s (current value %q)? [y/N] ", key, resp.Node.Value))
+	if err == ErrNotInteractive {
+		return false, 0, fmt.Errorf("set: -i %s", err)
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", resp.Node.ModifiedIndex, nil
+}
+
 // setupOptions builds a FlagSet and parses the args passed to the command.
 func (h *SetHandler) setupOptions(args []string) (*SetOptions, []string, error) {
 	opts := &SetOptions{}
 	flags := flag.NewFlagSet("set_flags", flag.ContinueOnError)
 	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
 	flags.Uint64Var(&opts.TTL, "t", 0, "Sets the TTL")
+	flags.BoolVar(&opts.NoClobber, "n", false, "Do not overwrite an existing object")
+	flags.BoolVar(&opts.Interactive, "i", false, "Prompt before overwriting an existing object")
 
 	err := flags.Parse(args)
 	if err != nil {
@@ -96,8 +159,8 @@ func (h *SetHandler) setupOptions(args []string) (*SetOptions, []string, error)
 
 	args = flags.Args()
 	if opts.PrintHelp || len(args) < 2 {
-		printCommandHelp(h, flags)
-		return nil, nil, nil
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
 	}
 
 	return opts, args, nil
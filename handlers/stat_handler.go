@@ -0,0 +1,202 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the stat command.
+type StatOptions struct {
+	PrintHelp bool
+	Output    string
+}
+
+// The metadata displayed by the "stat" command.
+type statInfo struct {
+	Key           string     `json:"key"`
+	Dir           bool       `json:"dir"`
+	Hidden        bool       `json:"hidden"`
+	Expiration    *time.Time `json:"expiration,omitempty"`
+	TTL           int64      `json:"ttl"`
+	CreatedIndex  uint64     `json:"createdIndex"`
+	ModifiedIndex uint64     `json:"modifiedIndex"`
+	ValueLength   int        `json:"valueLength"`
+	Children      int        `json:"children"`
+	EtcdIndex     uint64     `json:"etcdIndex"`
+	RaftTerm      uint64     `json:"raftTerm"`
+}
+
+// StatHandler handles the "stat" command.
+type StatHandler struct {
+	controller *Controller
+}
+
+// NewStatHandler returns a new StatHandler instance.
+func NewStatHandler(controller *Controller) *StatHandler {
+	return &StatHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *StatHandler) Command() string {
+	return "stat"
+}
+
+// Validate returns whether the user input is valid.
+func (h *StatHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *StatHandler) Syntax() string {
+	return "stat [options] <path>..."
+}
+
+// Description returns a string that describes the command.
+func (h *StatHandler) Description() string {
+	return "Displays the metadata of objects and keys"
+}
+
+// Handles the "stat" command.
+func (h *StatHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	output := bytes.NewBufferString("")
+	for index, arg := range args {
+		key := h.controller.WorkingDir(arg)
+		resp, err := h.controller.Client().Get(key, false, false)
+		if err != nil {
+			return output.String(), err
+		}
+
+		info := newStatInfo(resp, key)
+		if opts.Output == "json" {
+			b, err := json.Marshal(info)
+			if err != nil {
+				return output.String(), err
+			}
+			output.Write(b)
+			output.WriteString("\n")
+		} else {
+			if index > 0 {
+				output.WriteString("\n")
+			}
+			h.writeInfo(output, info)
+		}
+	}
+
+	return output.String(), nil
+}
+
+// writeInfo writes the metadata in the human readable format to the output.
+func (h *StatHandler) writeInfo(output *bytes.Buffer, info *statInfo) {
+	typeValue := "object"
+	if info.Dir {
+		typeValue = "key"
+	}
+	expires := "never"
+	if info.Expiration != nil {
+		remaining := info.Expiration.Sub(time.Now()).Seconds()
+		expires = fmt.Sprintf("%s (in %ds)", info.Expiration.Local().Format(time.RFC1123), int64(remaining))
+	}
+
+	fields := [][2]string{
+		{"Key", info.Key},
+		{"Type", typeValue},
+		{"Hidden", fmt.Sprintf("%t", info.Hidden)},
+		{"Value length", fmt.Sprintf("%d bytes", info.ValueLength)},
+		{"Children", fmt.Sprintf("%d", info.Children)},
+		{"TTL", fmt.Sprintf("%d", info.TTL)},
+		{"Expiration", expires},
+		{"Created index", fmt.Sprintf("%d", info.CreatedIndex)},
+		{"Modified index", fmt.Sprintf("%d", info.ModifiedIndex)},
+		{"Etcd index", fmt.Sprintf("%d", info.EtcdIndex)},
+		{"Raft term", fmt.Sprintf("%d", info.RaftTerm)},
+	}
+	for _, field := range fields {
+		output.WriteString(fmt.Sprintf("%15s: %s\n", field[0], field[1]))
+	}
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *StatHandler) setupOptions(args []string) (*StatOptions, []string, error) {
+	opts := &StatOptions{}
+	flags := flag.NewFlagSet("stat_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.StringVar(&opts.Output, "o", "", "Output format, use json for one JSON object per line")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) == 0 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+	if opts.Output != "" && opts.Output != "json" {
+		return nil, nil, fmt.Errorf("stat: unknown output format '%s'", opts.Output)
+	}
+
+	return opts, args, nil
+}
+
+// newStatInfo returns the metadata for the node in the response. The key is used when the
+// node has no key, which is the case for the root key.
+func newStatInfo(resp *etcd.Response, key string) *statInfo {
+	node := resp.Node
+	info := &statInfo{
+		Key:           node.Key,
+		Dir:           node.Dir,
+		Hidden:        strings.HasPrefix(path.Base(node.Key), "_"),
+		Expiration:    node.Expiration,
+		TTL:           node.TTL,
+		CreatedIndex:  node.CreatedIndex,
+		ModifiedIndex: node.ModifiedIndex,
+		ValueLength:   len(node.Value),
+		Children:      len(node.Nodes),
+		EtcdIndex:     resp.EtcdIndex,
+		RaftTerm:      resp.RaftTerm,
+	}
+	if info.Key == "" {
+		info.Key = key
+	}
+
+	return info
+}
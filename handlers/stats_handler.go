@@ -0,0 +1,196 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+)
+
+// StatsHandler handles the "stats" command.
+type StatsHandler struct {
+	controller *Controller
+}
+
+// NewStatsHandler returns a new StatsHandler instance.
+func NewStatsHandler(controller *Controller) *StatsHandler {
+	return &StatsHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *StatsHandler) Command() string {
+	return "stats"
+}
+
+// Validate returns whether the user input is valid.
+func (h *StatsHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *StatsHandler) Syntax() string {
+	return "stats [options] [self|store|leader]"
+}
+
+// Description returns a string that describes the command.
+func (h *StatsHandler) Description() string {
+	return "Displays the statistics of the server or the cluster leader"
+}
+
+// Handles the "stats" command.
+func (h *StatsHandler) Handle(i *Input) (string, error) {
+	opts, args, err := setupClusterOptions(h, h.controller.Stdout(), i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	kind := "self"
+	if len(args) > 0 {
+		kind = args[0]
+	}
+	if kind != "self" && kind != "store" && kind != "leader" {
+		return "", fmt.Errorf("stats: unknown statistics '%s', use self, store or leader", kind)
+	}
+
+	// Followers refuse requests for the leader statistics, so they are requested from the
+	// leader.
+	var body []byte
+	if kind == "leader" {
+		body, err = h.controller.leaderGet("/v2/stats/leader", nil)
+	} else {
+		body, err = h.controller.clusterGet("/v2/stats/"+kind, nil)
+	}
+	if err != nil {
+		return "", err
+	}
+	if opts.Output == "json" {
+		return indentJSON(body)
+	}
+
+	switch kind {
+	case "leader":
+		return h.leaderOutput(body)
+	case "self":
+		return h.selfOutput(body)
+	}
+	return h.storeOutput(body)
+}
+
+// selfOutput formats the statistics of the server.
+func (h *StatsHandler) selfOutput(body []byte) (string, error) {
+	stats := &selfStats{}
+	err := json.Unmarshal(body, stats)
+	if err != nil {
+		return "", err
+	}
+
+	output := bytes.NewBufferString("")
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", stats.Name)
+	fmt.Fprintf(w, "ID:\t%s\n", stats.ID)
+	fmt.Fprintf(w, "State:\t%s\n", stats.State)
+	fmt.Fprintf(w, "Start time:\t%s\n", stats.StartTime)
+	fmt.Fprintf(w, "Leader:\t%s\n", stats.LeaderInfo.Leader)
+	fmt.Fprintf(w, "Leader uptime:\t%s\n", stats.LeaderInfo.Uptime)
+	fmt.Fprintf(w, "Append requests received:\t%d\n", stats.RecvAppendRequestCnt)
+	fmt.Fprintf(w, "Append requests sent:\t%d\n", stats.SendAppendRequestCnt)
+	fmt.Fprintf(w, "Receive rate:\t%.2f req/s, %.2f B/s\n", stats.RecvPkgRate, stats.RecvBandwidthRate)
+	fmt.Fprintf(w, "Send rate:\t%.2f req/s, %.2f B/s\n", stats.SendPkgRate, stats.SendBandwidthRate)
+	w.Flush()
+
+	return output.String(), nil
+}
+
+// storeOutput formats the statistics of the store, one counter per line.
+func (h *StatsHandler) storeOutput(body []byte) (string, error) {
+	stats := make(map[string]interface{})
+	err := json.Unmarshal(body, &stats)
+	if err != nil {
+		return "", err
+	}
+
+	names := []string{}
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output := bytes.NewBufferString("")
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s:\t%v\n", name, stats[name])
+	}
+	w.Flush()
+
+	return output.String(), nil
+}
+
+// leaderOutput formats the statistics of the leader, with the latency and failure counts of
+// each follower.
+func (h *StatsHandler) leaderOutput(body []byte) (string, error) {
+	stats := &leaderStats{}
+	err := json.Unmarshal(body, stats)
+	if err != nil {
+		return "", err
+	}
+
+	// The member names are only used to make the output friendlier.
+	members := &clusterMembers{}
+	h.controller.clusterGet("/v2/members", members)
+
+	ids := []string{}
+	for id := range stats.Followers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	output := bytes.NewBufferString("")
+	output.WriteString(fmt.Sprintf("Leader: %s\n\n", memberName(members, stats.Leader)))
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "FOLLOWER\tCURRENT\tAVERAGE\tSTDDEV\tMIN\tMAX\tSUCCESS\tFAIL\t")
+	for _, id := range ids {
+		f := stats.Followers[id]
+		fmt.Fprintf(
+			w,
+			"%s\t%.3fms\t%.3fms\t%.3fms\t%.3fms\t%.3fms\t%d\t%d\t\n",
+			memberName(members, id),
+			f.Latency.Current,
+			f.Latency.Average,
+			f.Latency.StandardDeviation,
+			f.Latency.Minimum,
+			f.Latency.Maximum,
+			f.Counts.Success,
+			f.Counts.Fail,
+		)
+	}
+	w.Flush()
+
+	return output.String(), nil
+}
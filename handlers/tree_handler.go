@@ -0,0 +1,203 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/headzoo/etcdsh/env"
+)
+
+// Maximum number of characters displayed by "tree -v" for each value.
+const TreeValuePreviewLength = 40
+
+// Command line options for the tree command.
+type TreeOptions struct {
+	PrintHelp  bool
+	Level      int
+	DirsOnly   bool
+	ShowValues bool
+}
+
+// TreeHandler handles the "tree" command.
+type TreeHandler struct {
+	controller *Controller
+	colors     LsOutputColors
+	use_colors bool
+}
+
+// The number of keys and objects displayed by the "tree" command.
+type treeCounts struct {
+	keys    int
+	objects int
+}
+
+// NewTreeHandler returns a new TreeHandler instance.
+func NewTreeHandler(controller *Controller) *TreeHandler {
+	h := &TreeHandler{
+		controller: controller,
+	}
+	h.colors, h.use_colors = outputColors(controller.Config())
+
+	return h
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *TreeHandler) Command() string {
+	return "tree"
+}
+
+// Validate returns whether the user input is valid.
+func (h *TreeHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *TreeHandler) Syntax() string {
+	return "tree [options] <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *TreeHandler) Description() string {
+	return "Displays the keys and objects below a key as a tree"
+}
+
+// Handles the "tree" command.
+func (h *TreeHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	dir := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Get(dir, true, true)
+	if err != nil {
+		return "", err
+	}
+
+	output := bytes.NewBufferString("")
+	counts := &treeCounts{}
+	root := *resp.Node
+	root.Key = dir
+	output.WriteString(h.formatNode(&root, opts, true) + "\n")
+	h.writeChildren(output, resp.Node, "", 1, opts, counts)
+	output.WriteString(fmt.Sprintf("\n%d keys, %d objects\n", counts.keys, counts.objects))
+
+	return output.String(), nil
+}
+
+// writeChildren writes the children of node to the output, prefixing each line with the
+// connectors of the parent nodes.
+func (h *TreeHandler) writeChildren(output *bytes.Buffer, node *etcd.Node, prefix string, depth int, opts *TreeOptions, counts *treeCounts) {
+	children := etcd.Nodes{}
+	for _, n := range node.Nodes {
+		if n.Dir || !opts.DirsOnly {
+			children = append(children, n)
+		}
+	}
+
+	for index, n := range children {
+		connector, indent := "├── ", "│   "
+		if index == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		output.WriteString(prefix + connector + h.formatNode(n, opts, false) + "\n")
+
+		if n.Dir {
+			counts.keys++
+			if opts.Level == 0 || depth < opts.Level {
+				h.writeChildren(output, n, prefix+indent, depth+1, opts, counts)
+			}
+		} else {
+			counts.objects++
+		}
+	}
+}
+
+// formatNode returns the name of the node for display, along with a preview of the value
+// when requested. The full key is used for the root node.
+func (h *TreeHandler) formatNode(n *etcd.Node, opts *TreeOptions, root bool) string {
+	name := path.Base(n.Key)
+	if root {
+		name = n.Key
+	}
+	if h.use_colors {
+		color := h.colors.Object
+		if n.Dir {
+			color = h.colors.Key
+		}
+		name = env.ColorPrefixCode(color) + name + env.ColorPostfixCode()
+	}
+	if opts.ShowValues && !n.Dir {
+		name = fmt.Sprintf("%s = %s", name, previewValue(n.Value, TreeValuePreviewLength))
+	}
+
+	return name
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *TreeHandler) setupOptions(args []string) (*TreeOptions, []string, error) {
+	opts := &TreeOptions{}
+	flags := flag.NewFlagSet("tree_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.IntVar(&opts.Level, "L", 0, "Descend only this many levels deep")
+	flags.BoolVar(&opts.DirsOnly, "d", false, "List keys only")
+	flags.BoolVar(&opts.ShowValues, "v", false, "Show a preview of each value")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	return opts, args, nil
+}
+
+// previewValue returns the first line of value, shortened to at most length characters.
+func previewValue(value string, length int) string {
+	lines := strings.SplitN(value, "\n", 2)
+	preview := []rune(lines[0])
+	if len(preview) > length {
+		return string(preview[:length]) + "..."
+	}
+	if len(lines) > 1 {
+		return string(preview) + "..."
+	}
+
+	return string(preview)
+}
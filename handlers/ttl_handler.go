@@ -0,0 +1,184 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the ttl command.
+type TTLOptions struct {
+	PrintHelp bool
+	Recursive bool
+	Clear     bool
+}
+
+// TTLHandler handles the "ttl" command.
+type TTLHandler struct {
+	controller *Controller
+}
+
+// NewTTLHandler returns a new TTLHandler instance.
+func NewTTLHandler(controller *Controller) *TTLHandler {
+	return &TTLHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *TTLHandler) Command() string {
+	return "ttl"
+}
+
+// Validate returns whether the user input is valid.
+func (h *TTLHandler) Validate(i *Input) bool {
+	return len(i.Args) > 0
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *TTLHandler) Syntax() string {
+	return "ttl [options] <path> [seconds|duration]"
+}
+
+// Description returns a string that describes the command.
+func (h *TTLHandler) Description() string {
+	return "Displays or changes the TTL of an object or key without changing its value"
+}
+
+// Handles the "ttl" command.
+func (h *TTLHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	key := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Get(key, true, opts.Recursive)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) == 1 && !opts.Clear {
+		output := bytes.NewBufferString("")
+		h.writeTTL(output, resp.Node, opts.Recursive)
+		return output.String(), nil
+	}
+
+	ttl := uint64(0)
+	if !opts.Clear {
+		ttl, err = parseTTL(args[1])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	failed := []string{}
+	h.applyTTL(resp.Node, ttl, opts.Recursive, &failed)
+	if len(failed) > 0 {
+		return "", errors.New(strings.Join(failed, "\n"))
+	}
+	return "", nil
+}
+
+// writeTTL writes the remaining TTL of the node, and the nodes below it when recursive is
+// true, to the output.
+func (h *TTLHandler) writeTTL(output *bytes.Buffer, node *etcd.Node, recursive bool) {
+	output.WriteString(fmt.Sprintf("%s %d\n", node.Key, node.TTL))
+	if recursive {
+		for _, n := range node.Nodes {
+			h.writeTTL(output, n, recursive)
+		}
+	}
+}
+
+// applyTTL sets the TTL of the node, and the nodes below it when recursive is true. A ttl of
+// 0 makes the node permanent. Failures are appended to failed.
+func (h *TTLHandler) applyTTL(node *etcd.Node, ttl uint64, recursive bool, failed *[]string) {
+	client := h.controller.Client()
+	var err error
+	if node.Dir {
+		_, err = client.UpdateDir(node.Key, ttl)
+	} else {
+		_, err = client.CompareAndSwap(node.Key, node.Value, ttl, "", node.ModifiedIndex)
+		if etcdErrorCode(err) == EcodeTestFailed {
+			err = errors.New("modified by another client, TTL not changed")
+		}
+	}
+	if err != nil {
+		*failed = append(*failed, fmt.Sprintf("ttl: cannot change TTL of '%s': %s", node.Key, err))
+	}
+
+	if recursive {
+		for _, n := range node.Nodes {
+			h.applyTTL(n, ttl, recursive, failed)
+		}
+	}
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *TTLHandler) setupOptions(args []string) (*TTLOptions, []string, error) {
+	opts := &TTLOptions{}
+	flags := flag.NewFlagSet("ttl_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Recursive, "r", false, "Apply to every object and key below the path")
+	flags.BoolVar(&opts.Clear, "clear", false, "Remove the TTL, making the object permanent")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) == 0 || len(args) > 2 || (opts.Clear && len(args) > 1) {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
+
+// parseTTL parses a TTL given either as a number of seconds, or as a duration such as "90s"
+// or "5m".
+func parseTTL(s string) (uint64, error) {
+	seconds, err := strconv.ParseUint(s, 10, 64)
+	if err == nil {
+		return seconds, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("invalid TTL '%s', use seconds or a duration such as 90s or 5m", s)
+	}
+
+	return uint64(d / time.Second), nil
+}
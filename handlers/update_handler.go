@@ -0,0 +1,102 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"flag"
+	"fmt"
+)
+
+// UpdateHandler handles the "update" command.
+type UpdateHandler struct {
+	controller *Controller
+}
+
+// NewUpdateHandler returns a new UpdateHandler instance.
+func NewUpdateHandler(controller *Controller) *UpdateHandler {
+	return &UpdateHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *UpdateHandler) Command() string {
+	return "update"
+}
+
+// Validate returns whether the user input is valid.
+func (h *UpdateHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *UpdateHandler) Syntax() string {
+	return "update [options] <path> <value>"
+}
+
+// Description returns a string that describes the command.
+func (h *UpdateHandler) Description() string {
+	return "Updates an object, failing when it does not exist"
+}
+
+// Handles the "update" command.
+func (h *UpdateHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	key := h.controller.WorkingDir(args[0])
+	resp, err := h.controller.Client().Update(key, args[1], opts.TTL)
+	if err != nil {
+		if etcdErrorCode(err) == EcodeKeyNotFound {
+			err = fmt.Errorf("update: %s does not exist", key)
+		}
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\n", resp.Node.Value), nil
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *UpdateHandler) setupOptions(args []string) (*SetOptions, []string, error) {
+	opts := &SetOptions{}
+	flags := flag.NewFlagSet("update_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.Uint64Var(&opts.TTL, "t", 0, "Sets the TTL")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = flags.Args()
+	if opts.PrintHelp || len(args) < 2 {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, usageError(opts.PrintHelp)
+	}
+
+	return opts, args, nil
+}
@@ -0,0 +1,229 @@
+/**
+The MIT License (MIT)
+
+Copyright (c) 2014 Sean Hickey <sean@dulotech.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// Command line options for the watch command.
+type WatchOptions struct {
+	PrintHelp bool
+	Recursive bool
+	Index     uint64
+	Count     int
+	Output    string
+}
+
+// A watch event formatted for JSON output.
+type watchEvent struct {
+	Action        string `json:"action"`
+	Key           string `json:"key"`
+	PrevValue     string `json:"prevValue,omitempty"`
+	Value         string `json:"value,omitempty"`
+	Dir           bool   `json:"dir,omitempty"`
+	ModifiedIndex uint64 `json:"modifiedIndex"`
+}
+
+// WatchHandler handles the "watch" command.
+type WatchHandler struct {
+	controller *Controller
+}
+
+// NewWatchHandler returns a new WatchHandler instance.
+func NewWatchHandler(controller *Controller) *WatchHandler {
+	return &WatchHandler{
+		controller: controller,
+	}
+}
+
+// Command returns the string typed by the user that triggers to handler.
+func (h *WatchHandler) Command() string {
+	return "watch"
+}
+
+// Validate returns whether the user input is valid.
+func (h *WatchHandler) Validate(i *Input) bool {
+	return true
+}
+
+// Syntax returns a string that demonstrates how to use the command.
+func (h *WatchHandler) Syntax() string {
+	return "watch [options] <path>"
+}
+
+// Description returns a string that describes the command.
+func (h *WatchHandler) Description() string {
+	return "Displays changes to an object or key as they happen"
+}
+
+// Handles the "watch" command.
+//
+// Events are written to the controller output as they arrive, rather than being returned
+// when the command finishes. Pressing Ctrl-C stops the watch and returns to the prompt.
+func (h *WatchHandler) Handle(i *Input) (string, error) {
+	opts, args, err := h.setupOptions(i.Args)
+	if opts == nil || err != nil {
+		return "", err
+	}
+
+	key := h.controller.WorkingDir(args[0])
+	receiver := make(chan *etcd.Response)
+	stop := make(chan bool, 1)
+	done := make(chan error, 1)
+	go func() {
+		_, err := h.controller.Client().Watch(key, opts.Index, opts.Recursive, receiver, stop)
+		done <- err
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	count := 0
+	for {
+		select {
+		case resp, ok := <-receiver:
+			// The receiver is closed before the watch error is sent on done.
+			if !ok {
+				return "", watchError(<-done)
+			}
+			err = h.writeEvent(resp, opts)
+			if err != nil {
+				stopWatch(receiver, stop, done)
+				return "", err
+			}
+			count++
+			if opts.Count > 0 && count >= opts.Count {
+				stopWatch(receiver, stop, done)
+				return "", nil
+			}
+		case <-interrupt:
+			stopWatch(receiver, stop, done)
+			return "", nil
+		case err = <-done:
+			return "", watchError(err)
+		}
+	}
+}
+
+// writeEvent writes a single watch event to the controller output.
+func (h *WatchHandler) writeEvent(resp *etcd.Response, opts *WatchOptions) error {
+	event := watchEvent{
+		Action:        resp.Action,
+		Key:           resp.Node.Key,
+		Value:         resp.Node.Value,
+		Dir:           resp.Node.Dir,
+		ModifiedIndex: resp.Node.ModifiedIndex,
+	}
+	if resp.PrevNode != nil {
+		event.PrevValue = resp.PrevNode.Value
+	}
+
+	if opts.Output == "json" {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(h.controller.Stdout(), string(b))
+		return nil
+	}
+
+	fmt.Fprintf(
+		h.controller.Stdout(),
+		"[%d] %s %s: %q -> %q\n",
+		event.ModifiedIndex,
+		event.Action,
+		event.Key,
+		event.PrevValue,
+		event.Value,
+	)
+
+	return nil
+}
+
+// watchError returns the error which ended a watch, or nil when the watch was stopped by the
+// user.
+func watchError(err error) error {
+	if err == etcd.ErrWatchStoppedByUser {
+		return nil
+	}
+
+	return err
+}
+
+// setupOptions builds a FlagSet and parses the args passed to the command.
+func (h *WatchHandler) setupOptions(args []string) (*WatchOptions, []string, error) {
+	opts := &WatchOptions{}
+	flags := flag.NewFlagSet("watch_flags", flag.ContinueOnError)
+	flags.BoolVar(&opts.PrintHelp, "h", false, "Show the command help")
+	flags.BoolVar(&opts.Recursive, "r", false, "Watch every object below the key")
+	flags.Uint64Var(&opts.Index, "i", 0, "Start watching from this index")
+	flags.IntVar(&opts.Count, "n", 0, "Stop after this many events")
+	flags.StringVar(&opts.Output, "o", "", "Output format, use json for one JSON event per line")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PrintHelp {
+		printCommandHelp(h.controller.Stdout(), h, flags)
+		return nil, nil, nil
+	}
+	if opts.Output != "" && opts.Output != "json" {
+		return nil, nil, fmt.Errorf("watch: unknown output format '%s'", opts.Output)
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	return opts, args, nil
+}
+
+// stopWatch signals the watch to stop, and discards any events still being delivered so the
+// watching goroutine is not left blocked on the receiver.
+func stopWatch(receiver chan *etcd.Response, stop chan bool, done chan error) {
+	stop <- true
+	go func() {
+		for {
+			select {
+			case _, ok := <-receiver:
+				if !ok {
+					receiver = nil
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
@@ -27,7 +27,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/coreos/go-etcd/etcd"
 	"github.com/headzoo/etcdsh/config"
@@ -39,13 +41,19 @@ import (
 func main() {
 	conf := config.New()
 
-	help, version := false, false
+	help, version, exitOnError, command := false, false, false, ""
 	flag.BoolVar(&help, "help", false, "Prints command line options and exit.")
 	flag.BoolVar(&version, "version", false, "Prints the etcdsh version and exit.")
+	flag.StringVar(&command, "c", "", "Run these commands, separated by ';', and exit.")
+	flag.BoolVar(&exitOnError, "e", false, "Exit as soon as a command fails when not interactive.")
 	flag.StringVar(&conf.Machine, "machine", conf.Machine, "Connect to this etcd server.")
 	flag.StringVar(&conf.PS1, "ps1", conf.PS1, "First prompt format")
 	flag.StringVar(&conf.PS2, "ps2", conf.PS2, "Second prompt format")
 	flag.BoolVar(&conf.Colors, "colors", conf.Colors, "Use colors in display.")
+	flag.StringVar(&conf.Home, "home", conf.Home, "Directory used by cd without arguments.")
+	flag.StringVar(&conf.HistoryFile, "historyfile", conf.HistoryFile, "File the command history is saved to.")
+	flag.IntVar(&conf.HistorySize, "historysize", conf.HistorySize, "Number of commands saved in the history file.")
+	flag.BoolVar(&conf.HistoryIgnoreSpace, "historyignorespace", conf.HistoryIgnoreSpace, "Do not save commands starting with a space.")
 	flag.Parse()
 
 	if help {
@@ -57,7 +65,14 @@ func main() {
 		os.Exit(0)
 	}
 
-	fmt.Printf("Connecting to %s\n", conf.Machine)
+	script, err := openScript(command)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if script == nil {
+		fmt.Printf("Connecting to %s\n", conf.Machine)
+	}
 	client := etcd.NewClient([]string{conf.Machine})
 
 	controller := handlers.NewController(conf, client, os.Stdout, os.Stderr, os.Stdin)
@@ -66,14 +81,74 @@ func main() {
 	controller.Add(handlers.NewHelpHandler(controller))
 	controller.Add(handlers.NewCdHandler(controller))
 	controller.Add(handlers.NewGetHandler(controller))
+	controller.Add(handlers.NewRmHandler(controller))
+	controller.Add(handlers.NewMkdirHandler(controller))
+	controller.Add(handlers.NewCpHandler(controller))
+	controller.Add(handlers.NewMvHandler(controller))
+	controller.Add(handlers.NewTreeHandler(controller))
+	controller.Add(handlers.NewFindHandler(controller))
+	controller.Add(handlers.NewGrepHandler(controller))
+	controller.Add(handlers.NewWatchHandler(controller))
+	controller.Add(handlers.NewEditHandler(controller))
+	controller.Add(handlers.NewCasHandler(controller))
+	controller.Add(handlers.NewCadHandler(controller))
+	controller.Add(handlers.NewMkHandler(controller))
+	controller.Add(handlers.NewUpdateHandler(controller))
+	controller.Add(handlers.NewTTLHandler(controller))
+	controller.Add(handlers.NewStatHandler(controller))
+	controller.Add(handlers.NewPushHandler(controller))
+	controller.Add(handlers.NewPeekHandler(controller))
+	controller.Add(handlers.NewPopHandler(controller))
+	controller.Add(handlers.NewQlenHandler(controller))
+	controller.Add(handlers.NewLockHandler(controller))
+	controller.Add(handlers.NewExportHandler(controller))
+	controller.Add(handlers.NewImportHandler(controller))
+	controller.Add(handlers.NewDiffHandler(controller))
+	controller.Add(handlers.NewDuHandler(controller))
+	controller.Add(handlers.NewMembersHandler(controller))
+	controller.Add(handlers.NewLeaderHandler(controller))
+	controller.Add(handlers.NewStatsHandler(controller))
+	controller.Add(handlers.NewHealthHandler(controller))
+	controller.Add(handlers.NewPwdHandler(controller))
+	controller.Add(handlers.NewPushdHandler(controller))
+	controller.Add(handlers.NewPopdHandler(controller))
+	controller.Add(handlers.NewDirsHandler(controller))
+	controller.Add(handlers.NewHistoryHandler(controller))
+
+	if script != nil {
+		os.Exit(controller.Run(script, exitOnError))
+	}
 	os.Exit(controller.Start())
 }
 
+// openScript returns the commands to run when the shell is not interactive, which are either
+// the commands given with -c, the script file named by the first argument, or stdin when stdin
+// is not a terminal. Returns nil when the shell is interactive.
+func openScript(command string) (io.Reader, error) {
+	if command != "" {
+		return strings.NewReader(command), nil
+	}
+	if flag.NArg() > 0 {
+		file, err := os.Open(flag.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err == nil && stat.Mode()&os.ModeCharDevice == 0 {
+		return os.Stdin, nil
+	}
+
+	return nil, nil
+}
+
 // printHelp prints the command line help information.
 func printHelp() {
 	printVersion()
 	fmt.Println("USAGE:")
-	fmt.Println("\tetcdsh [OPTIONS]")
+	fmt.Println("\tetcdsh [OPTIONS] [SCRIPT]")
 
 	fmt.Println("")
 	fmt.Println("OPTIONS:")
@@ -84,6 +159,8 @@ func printHelp() {
 	fmt.Println("")
 	fmt.Println("EXAMPLES:")
 	fmt.Println("\tetcdsh -machine='http://192.168.1.23:4001'")
+	fmt.Println("\tetcdsh -c 'mkdir -p /apps; set /apps/version 1.0'")
+	fmt.Println("\tetcdsh -e provision.esh")
 
 	fmt.Println("")
 }